@@ -183,6 +183,25 @@ func line(n int) *Virtual {
 	return g
 }
 
+// visitList visits the vertices w ≥ a in the short list, skipping v and
+// duplicates, in numerical order. The list is sorted in place.
+func visitList(v int, list []int, a int, do func(w int, c int64) bool) (aborted bool) {
+	for i := 1; i < len(list); i++ {
+		for j := i; j > 0 && list[j] < list[j-1]; j-- {
+			list[j], list[j-1] = list[j-1], list[j]
+		}
+	}
+	for i, w := range list {
+		if w < a || w == v || i > 0 && w == list[i-1] {
+			continue
+		}
+		if do(w, 0) {
+			return true
+		}
+	}
+	return
+}
+
 // generic returns a standard implementation; cost and edge can't be nil.
 func generic(n int, cost CostFunc, edge func(v, w int) bool) *Virtual {
 	switch {
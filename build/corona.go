@@ -0,0 +1,74 @@
+package build
+
+import "strconv"
+
+// Corona returns the corona product of g1 and g2: the graph obtained by
+// taking one copy of g1 and one copy of g2 for each vertex of g1,
+// and joining each vertex of g1 to all vertices in its own copy of g2.
+// The edges of g1 and g2 keep their costs, and the new edges have zero cost.
+//
+// In the new graph, the vertices of g1 keep their indices, and vertex v
+// in the copy of g2 that belongs to vertex i ∊ g1 gets index m + n⋅i + v,
+// where m = g1.Order() and n = g2.Order().
+func (g1 *Virtual) Corona(g2 *Virtual) *Virtual {
+	m, n := g1.Order(), g2.Order()
+	switch {
+	case m == 0:
+		return null
+	case n == 0:
+		return g1
+	case m*n/m != n || m+m*n < 0:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+
+	g := generic(m+m*n, func(v, w int) int64 {
+		if v < m && w < m {
+			return g1.cost(v, w)
+		}
+		if v >= m && w >= m {
+			return g2.cost((v-m)%n, (w-m)%n)
+		}
+		return 0
+	}, func(v, w int) (edge bool) {
+		switch {
+		case v < m && w < m:
+			return g1.edge(v, w)
+		case v < m:
+			return (w-m)/n == v
+		case w < m:
+			return (v-m)/n == w
+		}
+		i, j := (v-m)/n, (w-m)/n
+		return i == j && g2.edge((v-m)%n, (w-m)%n)
+	})
+
+	g.degree = func(v int) (deg int) {
+		if v < m {
+			return g1.degree(v) + n
+		}
+		return 1 + g2.degree((v-m)%n)
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		if v < m {
+			if g1.visit(v, a, do) {
+				return true
+			}
+			for w := max(a, m+n*v); w < m+n*(v+1); w++ {
+				if do(w, 0) {
+					return true
+				}
+			}
+			return
+		}
+		i := (v - m) / n
+		if i >= a && do(i, 0) {
+			return true
+		}
+		start := m + n*i // first vertex in the copy of g2
+		return g2.visit(v-start, max(0, a-start), func(w int, c int64) bool {
+			return do(start+w, c)
+		})
+	}
+	return g
+}
@@ -0,0 +1,41 @@
+package build
+
+import "testing"
+
+func TestCorona(t *testing.T) {
+	res := Grid(1, 2).Corona(Kn(1))
+	if mess, diff := diff(res.String(), "4 [{0 1} {0 2} {1 3}]"); diff {
+		t.Errorf("Corona %s", mess)
+	}
+
+	res = Kn(1).Corona(Grid(1, 2))
+	if mess, diff := diff(res.String(), "3 [{0 1} {0 2} {1 2}]"); diff {
+		t.Errorf("Corona %s", mess)
+	}
+
+	res = Grid(1, 2).AddCost(5).Corona(Grid(1, 2).AddCost(3))
+	exp := "6 [{0 1}:5 {0 2} {0 3} {1 4} {1 5} {2 3}:3 {4 5}:3]"
+	if mess, diff := diff(res.String(), exp); diff {
+		t.Errorf("Corona %s", mess)
+	}
+	Consistent("Corona", t, res)
+
+	for _, g1 := range operands() {
+		for _, g2 := range operands() {
+			res := g1.Corona(g2)
+			m, n := g1.Order(), g2.Order()
+			checkEdges("Corona", t, res, func(v, w int) bool {
+				switch {
+				case v < m && w < m:
+					return g1.Edge(v, w)
+				case v < m:
+					return (w-m)/n == v
+				case w < m:
+					return (v-m)/n == w
+				}
+				return (v-m)/n == (w-m)/n && g2.Edge((v-m)%n, (w-m)%n)
+			})
+			Consistent("Corona", t, res)
+		}
+	}
+}
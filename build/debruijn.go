@@ -0,0 +1,117 @@
+package build
+
+import "strconv"
+
+// DeBruijn returns a virtual directed de Bruijn graph with kⁿ vertices,
+// which correspond to the strings of length n over an alphabet of k symbols.
+// There is an edge from each string to the k strings obtained by removing
+// its first symbol and appending a new symbol at the end.
+// The k self-loops at the constant strings are left out.
+//
+// The string s₁s₂…sₙ gets index s₁kⁿ⁻¹ + s₂kⁿ⁻² + … + sₙ, and hence there is
+// an edge from v to w whenever w = kv + j mod kⁿ for some j, 0 ≤ j < k.
+func DeBruijn(k, n int) *Virtual {
+	switch {
+	case k < 0 || n < 0:
+		return nil
+	case k == 0 && n > 0:
+		return null
+	case k <= 1 || n == 0:
+		return singleton()
+	case n == 1:
+		return Kn(k)
+	}
+	size := power(k, n)
+
+	// The successors of v are [first(v)..first(v)+k).
+	first := func(v int) int { return v % (size / k) * k }
+
+	g := generic0(size, func(v, w int) (edge bool) {
+		f := first(v)
+		return f <= w && w < f+k
+	})
+
+	g.degree = func(v int) int {
+		if f := first(v); f <= v && v < f+k {
+			return k - 1
+		}
+		return k
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		f := first(v)
+		for w := max(a, f); w < f+k; w++ {
+			if w != v && do(w, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return g
+}
+
+// Kautz returns a virtual directed Kautz graph with (k+1)kⁿ⁻¹ vertices,
+// which correspond to the strings of length n over an alphabet
+// of k+1 symbols in which no two consecutive symbols are equal.
+// There is an edge from each string to the k strings obtained by
+// removing its first symbol and appending a symbol different from
+// the last one.
+//
+// The string s₁s₂…sₙ is written as s₁d₂…dₙ, where dᵢ = sᵢ - sᵢ₋₁ - 1 mod k+1
+// is in the range 0..k-1, and gets index s₁kⁿ⁻¹ + d₂kⁿ⁻² + … + dₙ.
+// Hence the successors of a vertex form an interval of k consecutive vertices.
+func Kautz(k, n int) *Virtual {
+	switch {
+	case k < 0 || n < 0:
+		return nil
+	case n == 0 || k == 0 && n == 1:
+		return singleton()
+	case k == 0:
+		return null
+	case n == 1:
+		return Kn(k + 1)
+	}
+	tail := power(k, n-1) // number of strings d₂…dₙ
+	if (k+1)*tail/(k+1) != tail {
+		panic("too large k=" + strconv.Itoa(k) + " n=" + strconv.Itoa(n))
+	}
+	size := (k + 1) * tail
+
+	// The successors of v are [first(v)..first(v)+k).
+	first := func(v int) int {
+		s1, d := v/tail, v%tail
+		d2, rest := d/(tail/k), d%(tail/k)
+		s2 := (s1 + 1 + d2) % (k + 1)
+		return s2*tail + rest*k
+	}
+
+	g := generic0(size, func(v, w int) (edge bool) {
+		f := first(v)
+		return f <= w && w < f+k
+	})
+
+	g.degree = func(v int) int { return k }
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		f := first(v)
+		for w := max(a, f); w < f+k; w++ {
+			if do(w, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return g
+}
+
+// power returns kⁿ, where k ≥ 1 and n ≥ 0.
+func power(k, n int) int {
+	res := 1
+	for i := 0; i < n; i++ {
+		if k*res/k != res {
+			panic("too large k=" + strconv.Itoa(k) + " n=" + strconv.Itoa(n))
+		}
+		res *= k
+	}
+	return res
+}
@@ -0,0 +1,122 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+// words returns all strings of length n over the alphabet 0..k-1
+// in lexicographic order; if kautz is true, only strings in which
+// no two consecutive symbols are equal are included.
+func words(k, n int, kautz bool) (res [][]int) {
+	var gen func(s []int)
+	gen = func(s []int) {
+		if len(s) == n {
+			res = append(res, append([]int{}, s...))
+			return
+		}
+		for c := 0; c < k; c++ {
+			if kautz && len(s) > 0 && s[len(s)-1] == c {
+				continue
+			}
+			gen(append(s, c))
+		}
+	}
+	gen(nil)
+	return
+}
+
+// shifts returns a graph with an edge from s to t whenever t is obtained
+// by removing the first symbol of s and appending a symbol at the end.
+func shifts(list [][]int, index func(s []int) int) *graph.Mutable {
+	g := graph.New(len(list))
+	for _, s := range list {
+		for _, t := range list {
+			shift := true
+			for i := 1; i < len(s); i++ {
+				shift = shift && s[i] == t[i-1]
+			}
+			if v, w := index(s), index(t); shift && v != w {
+				g.Add(v, w)
+			}
+		}
+	}
+	return g
+}
+
+func TestDeBruijn(t *testing.T) {
+	if mess, diff := diff(DeBruijn(-1, 1), (*Virtual)(nil)); diff {
+		t.Errorf("DeBruijn %s", mess)
+	}
+
+	if mess, diff := diff(DeBruijn(0, 2).String(), "0 []"); diff {
+		t.Errorf("DeBruijn %s", mess)
+	}
+
+	if mess, diff := diff(DeBruijn(3, 0).String(), "1 []"); diff {
+		t.Errorf("DeBruijn %s", mess)
+	}
+
+	exp := "4 [(0 1) {1 2} (1 3) (2 0) (3 2)]"
+	if mess, diff := diff(DeBruijn(2, 2).String(), exp); diff {
+		t.Errorf("DeBruijn %s", mess)
+	}
+
+	for k := 0; k < 5; k++ {
+		for n := 0; n < 5; n++ {
+			res := DeBruijn(k, n)
+			index := func(s []int) (v int) {
+				for _, c := range s {
+					v = k*v + c
+				}
+				return
+			}
+			exp := shifts(words(k, n, false), index)
+			if mess, diff := diff(graph.Equal(res, exp), true); diff {
+				t.Errorf("DeBruijn(%d, %d) %s", k, n, mess)
+			}
+			Consistent("DeBruijn", t, res)
+		}
+	}
+}
+
+func TestKautz(t *testing.T) {
+	if mess, diff := diff(Kautz(1, -1), (*Virtual)(nil)); diff {
+		t.Errorf("Kautz %s", mess)
+	}
+
+	if mess, diff := diff(Kautz(0, 2).String(), "0 []"); diff {
+		t.Errorf("Kautz %s", mess)
+	}
+
+	if mess, diff := diff(Kautz(2, 0).String(), "1 []"); diff {
+		t.Errorf("Kautz %s", mess)
+	}
+
+	exp := "6 [(0 2) {0 3} {1 4} (1 5) (2 4) {2 5} (3 1) (4 0) (5 3)]"
+	if mess, diff := diff(Kautz(2, 2).String(), exp); diff {
+		t.Errorf("Kautz %s", mess)
+	}
+
+	for k := 0; k < 5; k++ {
+		for n := 0; n < 5; n++ {
+			res := Kautz(k, n)
+			index := func(s []int) (v int) {
+				for i, c := range s {
+					if i == 0 {
+						v = c
+						continue
+					}
+					d := (c - s[i-1] - 1 + k + 1) % (k + 1)
+					v = k*v + d
+				}
+				return
+			}
+			exp := shifts(words(k+1, n, true), index)
+			if mess, diff := diff(graph.Equal(res, exp), true); diff {
+				t.Errorf("Kautz(%d, %d) %s", k, n, mess)
+			}
+			Consistent("Kautz", t, res)
+		}
+	}
+}
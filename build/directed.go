@@ -0,0 +1,120 @@
+package build
+
+import "strconv"
+
+// DirectedCycle returns a virtual directed cycle graph with n vertices and
+// the edges (0, 1), (1, 2), (2, 3),… , (n-1, 0).
+func DirectedCycle(n int) *Virtual {
+	switch {
+	case n < 0:
+		return nil
+	case n <= 2:
+		return Cycle(n)
+	}
+
+	g := generic0(n, func(v, w int) (edge bool) {
+		return w == v+1 || v == n-1 && w == 0
+	})
+
+	g.degree = degreeOne
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		w := v + 1
+		if w == n {
+			w = 0
+		}
+		return w >= a && do(w, 0)
+	}
+	return g
+}
+
+// DirectedPath returns a virtual directed path with n vertices and
+// the edges (0, 1), (1, 2), (2, 3),… , (n-2, n-1).
+func DirectedPath(n int) *Virtual {
+	switch {
+	case n < 0:
+		return nil
+	case n <= 1:
+		return Empty(n)
+	}
+
+	g := generic0(n, func(v, w int) (edge bool) {
+		return w == v+1
+	})
+
+	g.degree = func(v int) int {
+		if v == n-1 {
+			return 0
+		}
+		return 1
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		w := v + 1
+		return w < n && w >= a && do(w, 0)
+	}
+	return g
+}
+
+// DirectedGrid returns a virtual directed grid graph, numbered as in Grid,
+// in which all edges point to the right or downwards: there is an edge
+// from point (x, y) to the points (x, y+1) and (x+1, y).
+// The grid is a directed acyclic graph with a single source 0
+// and a single sink mn - 1.
+//
+// Point (x, y) gets index nx + y, and index i corresponds to the point (i/n, i%n).
+func DirectedGrid(m, n int) *Virtual {
+	switch {
+	case m < 0 || n < 0:
+		return nil
+	case m == 0 || n == 0:
+		return null
+	case m*n/m != n:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+
+	g := generic0(m*n, func(v, w int) (edge bool) {
+		return w == v+n || w == v+1 && w%n != 0
+	})
+
+	g.degree = func(v int) (deg int) {
+		if v%n != n-1 { // right
+			deg++
+		}
+		if v < g.order-n { // down
+			deg++
+		}
+		return
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		if v%n != n-1 { // right
+			if w := v + 1; w >= a && do(w, 0) {
+				return true
+			}
+		}
+		if w := v + n; w < g.order && w >= a { // down
+			return do(w, 0)
+		}
+		return
+	}
+	return g
+}
+
+// OutTree returns a full k-ary tree, numbered as in Tree,
+// in which all edges point from the parent to the child.
+func OutTree(k, n int) *Virtual {
+	if n < 0 || k < 1 {
+		return nil
+	}
+	return Tree(k, n).Orient(nil)
+}
+
+// InTree returns a full k-ary tree, numbered as in Tree,
+// in which all edges point from the child to the parent.
+func InTree(k, n int) *Virtual {
+	if n < 0 || k < 1 {
+		return nil
+	}
+	return Tree(k, n).Orient(func(v, w int) bool { return v > w })
+}
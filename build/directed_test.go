@@ -0,0 +1,100 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestDirectedCycle(t *testing.T) {
+	if mess, diff := diff(DirectedCycle(-1), (*Virtual)(nil)); diff {
+		t.Errorf("DirectedCycle %s", mess)
+	}
+
+	if mess, diff := diff(DirectedCycle(2).String(), "2 [{0 1}]"); diff {
+		t.Errorf("DirectedCycle %s", mess)
+	}
+
+	if mess, diff := diff(DirectedCycle(4).String(), "4 [(0 1) (1 2) (2 3) (3 0)]"); diff {
+		t.Errorf("DirectedCycle %s", mess)
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("DirectedCycle", t, DirectedCycle(n))
+	}
+}
+
+func TestDirectedPath(t *testing.T) {
+	if mess, diff := diff(DirectedPath(-1), (*Virtual)(nil)); diff {
+		t.Errorf("DirectedPath %s", mess)
+	}
+
+	if mess, diff := diff(DirectedPath(1).String(), "1 []"); diff {
+		t.Errorf("DirectedPath %s", mess)
+	}
+
+	if mess, diff := diff(DirectedPath(4).String(), "4 [(0 1) (1 2) (2 3)]"); diff {
+		t.Errorf("DirectedPath %s", mess)
+	}
+
+	for n := 0; n < 10; n++ {
+		res := DirectedPath(n)
+		if mess, diff := diff(graph.Acyclic(res), true); diff {
+			t.Errorf("DirectedPath %s", mess)
+		}
+		Consistent("DirectedPath", t, res)
+	}
+}
+
+func TestDirectedGrid(t *testing.T) {
+	if mess, diff := diff(DirectedGrid(-1, 2), (*Virtual)(nil)); diff {
+		t.Errorf("DirectedGrid %s", mess)
+	}
+
+	if mess, diff := diff(DirectedGrid(0, 2).String(), "0 []"); diff {
+		t.Errorf("DirectedGrid %s", mess)
+	}
+
+	exp := "6 [(0 1) (0 3) (1 2) (1 4) (2 5) (3 4) (4 5)]"
+	if mess, diff := diff(DirectedGrid(2, 3).String(), exp); diff {
+		t.Errorf("DirectedGrid %s", mess)
+	}
+
+	for m := 0; m < 5; m++ {
+		for n := 0; n < 5; n++ {
+			res := DirectedGrid(m, n)
+			if mess, diff := diff(graph.Equal(res, Grid(m, n).Orient(nil)), true); diff {
+				t.Errorf("DirectedGrid(%d, %d) %s", m, n, mess)
+			}
+			Consistent("DirectedGrid", t, res)
+		}
+	}
+}
+
+func TestTrees(t *testing.T) {
+	if mess, diff := diff(OutTree(2, -1), (*Virtual)(nil)); diff {
+		t.Errorf("OutTree %s", mess)
+	}
+
+	if mess, diff := diff(InTree(0, 2), (*Virtual)(nil)); diff {
+		t.Errorf("InTree %s", mess)
+	}
+
+	if mess, diff := diff(OutTree(2, 2).String(), "3 [(0 1) (0 2)]"); diff {
+		t.Errorf("OutTree %s", mess)
+	}
+
+	if mess, diff := diff(InTree(2, 2).String(), "3 [(1 0) (2 0)]"); diff {
+		t.Errorf("InTree %s", mess)
+	}
+
+	for k := 1; k < 4; k++ {
+		for n := 0; n < 4; n++ {
+			out, in := OutTree(k, n), InTree(k, n)
+			if mess, diff := diff(graph.Equal(out, graph.Transpose(in)), true); diff {
+				t.Errorf("OutTree(%d, %d) %s", k, n, mess)
+			}
+			Consistent("OutTree", t, out)
+			Consistent("InTree", t, in)
+		}
+	}
+}
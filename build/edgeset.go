@@ -2,6 +2,7 @@ package build
 
 // EdgeSet describes a set of edges; an edge (v, w), v ≠ w, belongs to the set
 // if Keep(v, w) is true and (v, w) belongs to either From × To or To × From.
+// The Directed method restricts a set to the edges in From × To.
 // The zero value of an edge set is the universe, the set containing all edges.
 type EdgeSet struct {
 	From, To VertexSet
@@ -35,6 +36,23 @@ func Edge(v, w int) EdgeSet {
 	}
 }
 
+// DirectedEdge returns a set consisting of a single directed edge (v, w), v ≠ w,
+// of zero cost.
+func DirectedEdge(v, w int) EdgeSet {
+	return Edge(v, w).Directed()
+}
+
+// Directed returns the directed version of e: an edge (v, w) belongs to
+// the new set if it belongs to e and (v, w) ∊ From × To.
+func (e EdgeSet) Directed() EdgeSet {
+	keep := e.Keep
+	from, to := e.From, e.To
+	e.Keep = func(v, w int) bool {
+		return from.Contains(v) && to.Contains(w) && (keep == nil || keep(v, w))
+	}
+	return e
+}
+
 // Contains tells if the set contains the edge {v, w}.
 func (e EdgeSet) Contains(v, w int) bool {
 	switch {
@@ -165,3 +165,47 @@ func TestNewEdges(t *testing.T) {
 		}
 	}
 }
+
+func TestDirected(t *testing.T) {
+	res := Empty(3).Add(DirectedEdge(2, 0))
+	if mess, diff := diff(res.String(), "3 [(2 0)]"); diff {
+		t.Errorf("Directed %s", mess)
+	}
+	Consistent("Directed", t, res)
+
+	res = Empty(3).Add(DirectedEdge(1, 1))
+	if mess, diff := diff(res.String(), "3 []"); diff {
+		t.Errorf("Directed %s", mess)
+	}
+
+	e := EdgeSet{From: Range(0, 2), To: Range(1, 4), Cost: Cost(5)}.Directed()
+	res = Empty(4).Add(e)
+	exp := "4 [(0 1):5 (0 2):5 (0 3):5 (1 2):5 (1 3):5]"
+	if mess, diff := diff(res.String(), exp); diff {
+		t.Errorf("Directed %s", mess)
+	}
+	Consistent("Directed", t, res)
+
+	e = EdgeSet{To: Vertex(0), Keep: func(v, w int) bool { return v%2 == 0 }}.Directed()
+	res = Kn(4).Delete(e)
+	exp = "4 [{0 1} (0 2) {0 3} {1 2} {1 3} {2 3}]"
+	if mess, diff := diff(res.String(), exp); diff {
+		t.Errorf("Directed %s", mess)
+	}
+	Consistent("Directed", t, res)
+
+	// Join a source to the first vertex of a directed path,
+	// and the last vertex of the path to a sink.
+	res = Empty(1).Join(DirectedPath(3), EdgeSet{From: Vertex(0), To: Vertex(1)}.Directed())
+	res = res.Join(Empty(1), EdgeSet{From: Vertex(3), To: Vertex(4)}.Directed())
+	if mess, diff := diff(res.String(), "5 [(0 1) (1 2) (2 3) (3 4)]"); diff {
+		t.Errorf("Directed %s", mess)
+	}
+	Consistent("Directed", t, res)
+
+	res = Empty(2).Match(Empty(2), EdgeSet{From: Range(0, 2), To: Range(2, 4)}.Directed())
+	if mess, diff := diff(res.String(), "4 [(0 2) (1 3)]"); diff {
+		t.Errorf("Directed %s", mess)
+	}
+	Consistent("Directed", t, res)
+}
@@ -42,6 +42,64 @@ func Example_euclid() {
 	// path: [0 4 2] length: 8.246
 }
 
+// Find a shortest tour visiting a set of points in the plane.
+func Example_tsp() {
+	type Point struct{ x, y int }
+
+	// Euclidean distance.
+	Euclid := func(p, q Point) float64 {
+		xd := p.x - q.x
+		yd := p.y - q.y
+		return math.Sqrt(float64(xd*xd + yd*yd))
+	}
+
+	// 0  3  1
+	//
+	// 4  2  5
+	points := []Point{
+		{0, 0}, {4, 0}, {2, 2},
+		{2, 0}, {0, 2}, {4, 2},
+	}
+
+	// Build a complete graph with a cost function.
+	g := build.Kn(len(points)).AddCostFunc(func(v, w int) int64 {
+		// Distance to three decimal places.
+		return int64(1000 * Euclid(points[v], points[w]))
+	})
+
+	// An exact solution for small graphs.
+	tour, cost := graph.TSP(g)
+	fmt.Println("tour:", tour, "length:", float64(cost)/1000)
+
+	// A heuristic solution for larger graphs.
+	tour, cost = graph.TSPApprox(g)
+	fmt.Println("tour:", tour, "length:", float64(cost)/1000)
+	// Output:
+	// tour: [0 4 2 5 1 3] length: 12
+	// tour: [0 3 1 5 2 4] length: 12
+}
+
+// Compute independence numbers of some standard graphs.
+func Example_independentSet() {
+	// In a hypercube, the vertices with an even number
+	// of ones in their binary representation form
+	// a maximum independent set.
+	fmt.Println(graph.MaxIndependentSet(build.Hyper(3)))
+
+	// A grid graph is bipartite.
+	fmt.Println(len(graph.MaxIndependentSet(build.Grid(5, 5))))
+
+	// The circulant graph C₁₃(1, 3, 4) is the Paley graph of order 13.
+	paley := build.Circulant(13, 1, 3, 4)
+	fmt.Println(len(graph.MaxIndependentSet(paley)))
+	fmt.Println(len(graph.MinVertexCover(paley)))
+	// Output:
+	// [0 3 5 6]
+	// 13
+	// 3
+	// 10
+}
+
 // Find a maximum flow in a virtual grid graph.
 func Example_maxflow() {
 	// Build an undirected n×n grid with a silly edge cost.
@@ -202,6 +260,26 @@ func ExampleVirtual_Match_cube() {
 	// true
 }
 
+// Compare two cube graphs with different vertex labels.
+func Example_isomorphic() {
+	// A square prism is a cube.
+	prism := build.Cycle(4).Cartesian(build.Grid(1, 2))
+	cube := build.Hyper(3)
+	fmt.Println(graph.Equal(prism, cube))
+
+	mapping, ok := graph.Isomorphic(prism, cube)
+	fmt.Println(mapping, ok)
+
+	// The Wagner graph has the same degree sequence, but it isn't a cube.
+	wagner := build.Circulant(8, 1, 4)
+	_, ok = graph.Isomorphic(wagner, cube)
+	fmt.Println(ok)
+	// Output:
+	// false
+	// [0 1 2 3 6 7 4 5] true
+	// false
+}
+
 // Build a directed graph containing all edges (v, w) for which v is odd and w even.
 func ExampleGeneric() {
 	// Define a graph by a function.
@@ -219,3 +297,39 @@ func ExampleGeneric() {
 	// Acyclic: true
 	// [1 3 5 7 9 0 2 4 6 8]
 }
+
+// Compute the diameter of a large grid graph.
+func Example_diameter() {
+	g := build.Grid(1000, 1000)
+	fmt.Println(graph.Diameter(g))
+	// Output: 1998
+}
+
+// Find a maximum flow in a virtual directed grid.
+func ExampleDirectedGrid() {
+	// All edges point to the right or downwards, and have capacity 1.
+	n := 10
+	g := build.DirectedGrid(n, n).AddCost(1)
+
+	// Only two edges leave the top left corner.
+	flow, _ := graph.MaxFlow(g, 0, n*n-1)
+	fmt.Println(flow)
+	// Output: 2
+}
+
+// Turn an undirected graph into a directed acyclic graph.
+func ExampleVirtual_Orient() {
+	// Point each edge from the smaller vertex to the larger one:
+	// away from the hub, and clockwise along the rim,
+	// except for the edge from 1 to 5.
+	g := build.Wheel(5).Orient(nil)
+	fmt.Println(g)
+
+	order, acyclic := graph.TopSort(g)
+	fmt.Println("Acyclic:", acyclic)
+	fmt.Println(order)
+	// Output:
+	// 6 [(0 1) (0 2) (0 3) (0 4) (0 5) (1 2) (1 5) (2 3) (3 4) (4 5)]
+	// Acyclic: true
+	// [0 1 2 3 4 5]
+}
@@ -45,3 +45,11 @@ func Kmn(m, n int) *Virtual {
 	}
 	return res
 }
+
+// Star returns a virtual star graph with n+1 vertices:
+// a center 0 adjacent to each of the vertices 1, 2,… , n.
+//
+// The star is the complete bipartite graph Kmn(1, n).
+func Star(n int) *Virtual {
+	return Kmn(1, n)
+}
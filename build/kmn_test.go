@@ -21,3 +21,21 @@ func TestKmn(t *testing.T) {
 		}
 	}
 }
+
+func TestStar(t *testing.T) {
+	if mess, diff := diff(Star(-1), (*Virtual)(nil)); diff {
+		t.Errorf("Star %s", mess)
+	}
+
+	if mess, diff := diff(Star(0).String(), "1 []"); diff {
+		t.Errorf("Star %s", mess)
+	}
+
+	if mess, diff := diff(Star(3).String(), "4 [{0 1} {0 2} {0 3}]"); diff {
+		t.Errorf("Star %s", mess)
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("Star", t, Star(n))
+	}
+}
@@ -0,0 +1,65 @@
+package build
+
+import "strconv"
+
+// Ladder returns a virtual ladder graph with n rungs and 2n vertices.
+// The two vertices of rung i are 2i and 2i + 1, and the rails
+// connect rung i to rung i + 1.
+//
+// The ladder is the grid Grid(n, 2).
+func Ladder(n int) *Virtual {
+	return Grid(n, 2)
+}
+
+// Prism returns a virtual prism graph with n rungs and 2n vertices:
+// a ladder whose rails are closed into two cycles by connecting
+// rung n-1 to rung 0. The vertices are numbered as in Ladder.
+//
+// The prism is the torus Torus(n, 2).
+func Prism(n int) *Virtual {
+	return Torus(n, 2)
+}
+
+// MoebiusLadder returns a virtual Möbius ladder with n rungs and 2n vertices:
+// a ladder whose rails are closed into a single cycle by the two edges
+// {2n-2, 1} and {2n-1, 0}. The vertices are numbered as in Ladder.
+func MoebiusLadder(n int) *Virtual {
+	switch {
+	case n < 0:
+		return nil
+	case n <= 1:
+		return Ladder(n)
+	case n == 2:
+		return Kn(4)
+	case 2*n <= 0:
+		panic("too large n=" + strconv.Itoa(n))
+	}
+	last := 2*n - 2 // first vertex of the last rung
+
+	g := generic0(2*n, func(v, w int) (edge bool) {
+		switch {
+		case v/2 == w/2: // rung
+			return true
+		case v%2 == w%2: // rail
+			return v-w == 2 || w-v == 2
+		}
+		return v == last && w == 1 || v == last+1 && w == 0 ||
+			w == last && v == 1 || w == last+1 && v == 0
+	})
+
+	g.degree = func(v int) int { return 3 }
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		var list [3]int
+		switch {
+		case v < 2:
+			list = [3]int{1 - v, v + 2, last + 1 - v}
+		case v >= last:
+			list = [3]int{last + 1 - v, v - 2, v ^ 1}
+		default:
+			list = [3]int{v - 2, v ^ 1, v + 2}
+		}
+		return visitList(v, list[:], a, do)
+	}
+	return g
+}
@@ -0,0 +1,89 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestLadder(t *testing.T) {
+	if mess, diff := diff(Ladder(-1), (*Virtual)(nil)); diff {
+		t.Errorf("Ladder %s", mess)
+	}
+
+	if mess, diff := diff(Ladder(0).String(), "0 []"); diff {
+		t.Errorf("Ladder %s", mess)
+	}
+
+	exp := "6 [{0 1} {0 2} {1 3} {2 3} {2 4} {3 5} {4 5}]"
+	if mess, diff := diff(Ladder(3).String(), exp); diff {
+		t.Errorf("Ladder %s", mess)
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("Ladder", t, Ladder(n))
+	}
+}
+
+func TestPrism(t *testing.T) {
+	if mess, diff := diff(Prism(-1), (*Virtual)(nil)); diff {
+		t.Errorf("Prism %s", mess)
+	}
+
+	if mess, diff := diff(Prism(2).String(), "4 [{0 1} {0 2} {1 3} {2 3}]"); diff {
+		t.Errorf("Prism %s", mess)
+	}
+
+	exp := "6 [{0 1} {0 2} {0 4} {1 3} {1 5} {2 3} {2 4} {3 5} {4 5}]"
+	if mess, diff := diff(Prism(3).String(), exp); diff {
+		t.Errorf("Prism %s", mess)
+	}
+
+	for n := 3; n < 10; n++ {
+		exp := Cycle(n).Cartesian(Kn(2))
+		if mess, diff := diff(graph.Equal(Prism(n), exp), true); diff {
+			t.Errorf("Prism(%d) %s", n, mess)
+		}
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("Prism", t, Prism(n))
+	}
+}
+
+func TestMoebiusLadder(t *testing.T) {
+	if mess, diff := diff(MoebiusLadder(-1), (*Virtual)(nil)); diff {
+		t.Errorf("MoebiusLadder %s", mess)
+	}
+
+	if mess, diff := diff(MoebiusLadder(1).String(), "2 [{0 1}]"); diff {
+		t.Errorf("MoebiusLadder %s", mess)
+	}
+
+	if mess, diff := diff(graph.Equal(MoebiusLadder(2), Kn(4)), true); diff {
+		t.Errorf("MoebiusLadder %s", mess)
+	}
+
+	// The Möbius ladder with three rungs is the utility graph K3,3.
+	exp := "6 [{0 1} {0 2} {0 5} {1 3} {1 4} {2 3} {2 4} {3 5} {4 5}]"
+	if mess, diff := diff(MoebiusLadder(3).String(), exp); diff {
+		t.Errorf("MoebiusLadder %s", mess)
+	}
+	if _, ok := graph.Bipartition(MoebiusLadder(3)); !ok {
+		t.Errorf("MoebiusLadder(3) is not bipartite")
+	}
+
+	for n := 3; n < 10; n++ {
+		// The Möbius ladder is the circulant graph C(2n; 1, n),
+		// and it's not isomorphic to the prism.
+		if _, ok := graph.Isomorphic(MoebiusLadder(n), Circulant(2*n, 1, n)); !ok {
+			t.Errorf("MoebiusLadder(%d) isn't isomorphic to Circulant(%d, 1, %d)", n, 2*n, n)
+		}
+		if _, ok := graph.Isomorphic(MoebiusLadder(n), Prism(n)); ok {
+			t.Errorf("MoebiusLadder(%d) is isomorphic to Prism(%d)", n, n)
+		}
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("MoebiusLadder", t, MoebiusLadder(n))
+	}
+}
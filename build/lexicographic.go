@@ -0,0 +1,59 @@
+package build
+
+import "strconv"
+
+// LexicographicProduct returns the lexicographic product of g1 and g2,
+// also known as the composition g1[g2]: a graph whose vertices correspond
+// to ordered pairs (v1, v2), where v1 and v2 are vertices in g1 and g2,
+// respectively. The vertices (v1, v2) and (w1, w2) are connected by an edge
+// if {v1, w1} ∊ g1, or v1 = w1 and {v2, w2} ∊ g2.
+// In other words, each vertex of g1 is replaced by a copy of g2,
+// and each edge of g1 by all edges between the two copies.
+//
+// In the new graph, vertex (v1, v2) gets index n⋅v1 + v2, where n = g2.Order(),
+// and index i corresponds to the vertice (i/n, i%n).
+func (g1 *Virtual) LexicographicProduct(g2 *Virtual) *Virtual {
+	m, n := g1.Order(), g2.Order()
+	switch {
+	case m == 0 || n == 0:
+		return null
+	case m*n/m != n:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+
+	g := generic0(m*n, func(v, w int) (edge bool) {
+		v1, v2 := v/n, v%n
+		w1, w2 := w/n, w%n
+		if v1 == w1 {
+			return g2.edge(v2, w2)
+		}
+		return g1.edge(v1, w1)
+	})
+
+	g.degree = func(v int) (deg int) {
+		return g1.degree(v/n)*n + g2.degree(v%n)
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		v1, v2 := v/n, v%n
+		a1, a2 := a/n, a%n
+		return visitClosed(g1, v1, a1, func(w1 int) (skip bool) {
+			b := 0
+			if w1 == a1 {
+				b = a2
+			}
+			if w1 == v1 {
+				return g2.visit(v2, b, func(w2 int, _ int64) (skip bool) {
+					return do(n*w1+w2, 0)
+				})
+			}
+			for w2 := b; w2 < n; w2++ {
+				if do(n*w1+w2, 0) {
+					return true
+				}
+			}
+			return
+		})
+	}
+	return g
+}
@@ -0,0 +1,27 @@
+package build
+
+import "testing"
+
+func TestLexicographicProduct(t *testing.T) {
+	res := Grid(1, 2).LexicographicProduct(Empty(2))
+	if mess, diff := diff(res.String(), "4 [{0 2} {0 3} {1 2} {1 3}]"); diff {
+		t.Errorf("LexicographicProduct %s", mess)
+	}
+
+	res = Empty(2).LexicographicProduct(Grid(1, 2))
+	if mess, diff := diff(res.String(), "4 [{0 1} {2 3}]"); diff {
+		t.Errorf("LexicographicProduct %s", mess)
+	}
+
+	for _, g1 := range operands() {
+		for _, g2 := range operands() {
+			res := g1.LexicographicProduct(g2)
+			n := g2.Order()
+			checkEdges("LexicographicProduct", t, res, func(v, w int) bool {
+				v1, v2, w1, w2 := v/n, v%n, w/n, w%n
+				return g1.Edge(v1, w1) || v1 == w1 && g2.Edge(v2, w2)
+			})
+			Consistent("LexicographicProduct", t, res)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package build
+
+import "sort"
+
+// LineGraph returns the line graph of g: a graph whose vertices correspond
+// to the edges of g. Two vertices are adjacent if the corresponding edges
+// share an endpoint. The graph g must be undirected: each edge (v, w) in g
+// must be matched by an edge (w, v).
+//
+// The edges {v, w}, v < w, are numbered in lexicographic order:
+// edge {v, w} gets index s(v) + r, where s(v) is the number of edges {u, x},
+// u < x, for which u < v, and r is the number of neighbors x of v
+// for which v < x < w.
+//
+// The line graph stores the number of edges starting at each vertex,
+// which takes time O(|E| + |V|) and space O(|V|) to compute.
+func (g *Virtual) LineGraph() *Virtual {
+	n := g.order
+	// The edges {v, w}, v < w, have indices [start[v]..start[v+1]).
+	start := make([]int, n+1)
+	for v := 0; v < n; v++ {
+		deg := 0
+		g.visit(v, v+1, func(int, int64) (skip bool) {
+			deg++
+			return
+		})
+		start[v+1] = start[v] + deg
+	}
+	size := start[n]
+	switch size {
+	case 0:
+		return null
+	case 1:
+		return singleton()
+	}
+
+	// ends returns the endpoints v < w of edge e.
+	ends := func(e int) (v, w int) {
+		v = sort.SearchInts(start, e+1) - 1
+		r := e - start[v]
+		g.visit(v, v+1, func(x int, _ int64) (skip bool) {
+			if r == 0 {
+				w = x
+				return true
+			}
+			r--
+			return
+		})
+		return
+	}
+
+	// index returns the index of edge {v, w}, v < w.
+	index := func(v, w int) (e int) {
+		e = start[v]
+		g.visit(v, v+1, func(x int, _ int64) (skip bool) {
+			if x == w {
+				return true
+			}
+			e++
+			return
+		})
+		return
+	}
+
+	// incident returns the indices of the edges at v in increasing order.
+	incident := func(v int, list []int) []int {
+		// The indices of the edges {u, v}, u < v, are smaller than start[v],
+		// and increase with u. The indices of the edges {v, x}, v < x,
+		// are start[v], start[v]+1,…
+		e := start[v]
+		g.visit(v, 0, func(u int, _ int64) (skip bool) {
+			if u < v {
+				list = append(list, index(u, v))
+			} else {
+				list = append(list, e)
+				e++
+			}
+			return
+		})
+		return list
+	}
+
+	res := generic0(size, func(e, f int) (edge bool) {
+		v1, w1 := ends(e)
+		v2, w2 := ends(f)
+		return v1 == v2 || v1 == w2 || w1 == v2 || w1 == w2
+	})
+
+	res.degree = func(e int) int {
+		v, w := ends(e)
+		return g.degree(v) + g.degree(w) - 2
+	}
+
+	res.visit = func(e int, a int, do func(f int, c int64) bool) (aborted bool) {
+		v, w := ends(e)
+		l1 := incident(v, nil)
+		l2 := incident(w, make([]int, 0, g.degree(w)))
+		// Merge the two lists; e is the only edge present in both.
+		for i, j := 0, 0; i < len(l1) || j < len(l2); {
+			var f int
+			if j == len(l2) || i < len(l1) && l1[i] < l2[j] {
+				f = l1[i]
+				i++
+			} else {
+				f = l2[j]
+				j++
+			}
+			if f >= a && f != e && do(f, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return res
+}
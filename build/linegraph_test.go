@@ -0,0 +1,52 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestLineGraph(t *testing.T) {
+	if mess, diff := diff(Empty(3).LineGraph().String(), "0 []"); diff {
+		t.Errorf("LineGraph %s", mess)
+	}
+
+	if mess, diff := diff(Kn(2).LineGraph().String(), "1 []"); diff {
+		t.Errorf("LineGraph %s", mess)
+	}
+
+	// The edges of the star are {0, 1}, {0, 2} and {0, 3}.
+	if mess, diff := diff(graph.Equal(Star(3).LineGraph(), Kn(3)), true); diff {
+		t.Errorf("LineGraph %s", mess)
+	}
+
+	// The edges of the path are {0, 1}, {1, 2} and {2, 3}.
+	if mess, diff := diff(Grid(1, 4).LineGraph().String(), "3 [{0 1} {1 2}]"); diff {
+		t.Errorf("LineGraph %s", mess)
+	}
+
+	// The line graph of K4 is the octahedron K2,2,2.
+	if _, ok := graph.Isomorphic(Kn(4).LineGraph(), CompleteMultipartite(2, 2, 2)); !ok {
+		t.Errorf("LineGraph(K4) isn't isomorphic to K2,2,2")
+	}
+
+	for _, g := range []*Virtual{null, Kn(1), Kn(3), Kn(5), Cycle(6), Grid(3, 4), Tree(3, 3), Wheel(5), Empty(3).Join(Kn(3), Edge(2, 3))} {
+		var edges [][2]int
+		for v := 0; v < g.Order(); v++ {
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				if v < w {
+					edges = append(edges, [2]int{v, w})
+				}
+				return
+			})
+		}
+		res := g.LineGraph()
+		if mess, diff := diff(res.Order(), len(edges)); diff {
+			t.Errorf("LineGraph: order %s", mess)
+		}
+		checkEdges("LineGraph", t, res, func(e, f int) bool {
+			v1, w1, v2, w2 := edges[e][0], edges[e][1], edges[f][0], edges[f][1]
+			return v1 == v2 || v1 == w2 || w1 == v2 || w1 == w2
+		})
+		Consistent("LineGraph", t, res)
+	}
+}
@@ -0,0 +1,64 @@
+package build
+
+import (
+	"sort"
+	"strconv"
+)
+
+// CompleteMultipartite returns a virtual complete multipartite graph
+// whose vertices are divided into parts of the given sizes.
+// The first part consists of the vertices [0..sizes[0]), the second of the
+// vertices [sizes[0]..sizes[0]+sizes[1]), and so on. Two vertices are adjacent
+// if and only if they belong to different parts.
+func CompleteMultipartite(sizes ...int) *Virtual {
+	// Part i consists of the vertices [start[i]..start[i+1]).
+	start := make([]int, len(sizes)+1)
+	for i, size := range sizes {
+		if size < 0 {
+			return nil
+		}
+		start[i+1] = start[i] + size
+		if start[i+1] < start[i] {
+			panic("too large size=" + strconv.Itoa(size))
+		}
+	}
+	n := start[len(sizes)]
+	switch {
+	case n <= 1:
+		return Empty(n)
+	case len(sizes) == 2:
+		return Kmn(sizes[0], sizes[1])
+	}
+
+	// part returns the first and last+1 vertices of the part containing v.
+	part := func(v int) (lo, hi int) {
+		i := sort.SearchInts(start, v+1) - 1
+		return start[i], start[i+1]
+	}
+
+	g := generic0(n, func(v, w int) (edge bool) {
+		lo, hi := part(v)
+		return w < lo || w >= hi
+	})
+
+	g.degree = func(v int) int {
+		lo, hi := part(v)
+		return n - (hi - lo)
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		lo, hi := part(v)
+		for w := a; w < lo; w++ {
+			if do(w, 0) {
+				return true
+			}
+		}
+		for w := max(a, hi); w < n; w++ {
+			if do(w, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return g
+}
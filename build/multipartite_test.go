@@ -0,0 +1,37 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestCompleteMultipartite(t *testing.T) {
+	if mess, diff := diff(CompleteMultipartite(1, -1), (*Virtual)(nil)); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	if mess, diff := diff(CompleteMultipartite().String(), "0 []"); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	if mess, diff := diff(CompleteMultipartite(3).String(), "3 []"); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	exp := "5 [{0 1} {0 2} {0 3} {0 4} {1 3} {1 4} {2 3} {2 4}]"
+	if mess, diff := diff(CompleteMultipartite(1, 0, 2, 2).String(), exp); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	if mess, diff := diff(graph.Equal(CompleteMultipartite(1, 1, 1, 1), Kn(4)), true); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	if mess, diff := diff(graph.Equal(CompleteMultipartite(2, 3), Kmn(2, 3)), true); diff {
+		t.Errorf("CompleteMultipartite %s", mess)
+	}
+
+	for _, sizes := range [][]int{{}, {0}, {0, 0}, {1, 2, 3}, {3, 0, 1, 0, 2}, {2, 2, 2, 2}, {0, 4, 0}} {
+		Consistent("CompleteMultipartite", t, CompleteMultipartite(sizes...))
+	}
+}
@@ -0,0 +1,34 @@
+package build
+
+// Orient returns a directed graph in which each undirected edge {v, w} of g
+// is given a single direction: the edge (v, w) is kept if forward(v, w)
+// is true, and the edge (w, v) otherwise. For this to work, forward(v, w)
+// and forward(w, v) must differ. Directed edges of g, edges (v, w) for which
+// g has no edge (w, v), are kept as they are. The edges keep their costs.
+//
+// The nil value of forward points each edge from the smaller vertex to the
+// larger one; the result is then a directed acyclic graph.
+func (g *Virtual) Orient(forward FilterFunc) *Virtual {
+	n := g.order
+	switch n {
+	case 0:
+		return null
+	case 1:
+		return singleton()
+	}
+	if forward == nil {
+		forward = func(v, w int) bool { return v < w }
+	}
+	keep := func(v, w int) bool {
+		return forward(v, w) || !g.edge(w, v)
+	}
+	res := generic(n, g.cost, func(v, w int) bool {
+		return g.edge(v, w) && keep(v, w)
+	})
+	res.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		return g.visit(v, a, func(w int, c int64) bool {
+			return keep(v, w) && do(w, c)
+		})
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestOrient(t *testing.T) {
+	res := Cycle(4).Orient(nil)
+	if mess, diff := diff(res.String(), "4 [(0 1) (0 3) (1 2) (2 3)]"); diff {
+		t.Errorf("Orient %s", mess)
+	}
+	Consistent("Orient", t, res)
+
+	// Point the edges of the cycle from v to v+1 mod n.
+	n := 5
+	res = Cycle(n).AddCost(2).Orient(func(v, w int) bool { return w == (v+1)%n })
+	if mess, diff := diff(res.String(), "5 [(0 1):2 (1 2):2 (2 3):2 (3 4):2 (4 0):2]"); diff {
+		t.Errorf("Orient %s", mess)
+	}
+	Consistent("Orient", t, res)
+
+	// Directed edges are kept.
+	res = DirectedCycle(3).Orient(nil)
+	if mess, diff := diff(graph.Equal(res, DirectedCycle(3)), true); diff {
+		t.Errorf("Orient %s", mess)
+	}
+	Consistent("Orient", t, res)
+
+	for _, g := range operands() {
+		res := g.Orient(nil)
+		if graph.Equal(g, graph.Transpose(g)) && !graph.Acyclic(res) {
+			t.Errorf("Orient: %v not acyclic", res)
+		}
+		Consistent("Orient", t, res)
+	}
+}
@@ -0,0 +1,71 @@
+package build
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Permute returns a copy of g whose vertices have been renumbered:
+// vertex v ∊ g becomes perm[v] in the new graph, and each edge (v, w)
+// becomes (perm[v], perm[w]) with the same cost.
+// The slice perm must be a permutation of the vertices 0..n-1 of g.
+//
+// The new graph stores the permutation and its inverse.
+// Its Visit method sorts the neighbors of a vertex before visiting them.
+func (g *Virtual) Permute(perm []int) *Virtual {
+	n := g.order
+	if len(perm) != n {
+		panic("permutation length " + strconv.Itoa(len(perm)) + " != " + strconv.Itoa(n))
+	}
+	p := make([]int, n)
+	inv := make([]int, n)
+	for i := range inv {
+		inv[i] = -1
+	}
+	for v, pv := range perm {
+		if pv < 0 || pv >= n || inv[pv] != -1 {
+			panic("not a permutation: " + strconv.Itoa(pv))
+		}
+		p[v], inv[pv] = pv, v
+	}
+	switch n {
+	case 0:
+		return null
+	case 1:
+		return singleton()
+	}
+
+	res := &Virtual{
+		order: n,
+		edge: func(v, w int) bool {
+			return g.edge(inv[v], inv[w])
+		},
+		cost: func(v, w int) int64 {
+			return g.cost(inv[v], inv[w])
+		},
+		degree: func(v int) int {
+			return g.degree(inv[v])
+		},
+	}
+	res.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		type neighbor struct {
+			w int
+			c int64
+		}
+		var list []neighbor
+		g.visit(inv[v], 0, func(w int, c int64) (skip bool) {
+			if w := p[w]; w >= a {
+				list = append(list, neighbor{w, c})
+			}
+			return
+		})
+		sort.Slice(list, func(i, j int) bool { return list[i].w < list[j].w })
+		for _, e := range list {
+			if do(e.w, e.c) {
+				return true
+			}
+		}
+		return
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package build
+
+import "testing"
+
+func TestPermute(t *testing.T) {
+	res := Grid(1, 3).Permute([]int{1, 0, 2})
+	if mess, diff := diff(res.String(), "3 [{0 1} {0 2}]"); diff {
+		t.Errorf("Permute %s", mess)
+	}
+	Consistent("Permute", t, res)
+
+	res = Grid(1, 3).AddCostFunc(func(v, w int) int64 { return int64(v + w) }).Permute([]int{2, 1, 0})
+	if mess, diff := diff(res.String(), "3 [{0 1}:3 {1 2}:1]"); diff {
+		t.Errorf("Permute %s", mess)
+	}
+	Consistent("Permute", t, res)
+
+	for _, g := range operands() {
+		n := g.Order()
+		rotate, reverse := make([]int, n), make([]int, n)
+		for v := 0; v < n; v++ {
+			rotate[v], reverse[v] = (v+1)%n, n-1-v
+		}
+		for _, perm := range [][]int{rotate, reverse} {
+			res := g.Permute(perm)
+			for v := 0; v < n; v++ {
+				for w := 0; w < n; w++ {
+					if res.Edge(perm[v], perm[w]) != g.Edge(v, w) {
+						t.Errorf("Permute: edge (%d, %d) not mapped to (%d, %d)", v, w, perm[v], perm[w])
+					}
+				}
+			}
+			Consistent("Permute", t, res)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Permute: no panic for invalid permutation")
+		}
+	}()
+	Kn(3).Permute([]int{0, 1, 1})
+}
@@ -0,0 +1,54 @@
+package build
+
+import "strconv"
+
+// GeneralizedPetersen returns a virtual generalized Petersen graph
+// with 2n vertices. The outer vertices 0..n-1 form a cycle, in which
+// vertex i is adjacent to i+1 mod n, and each outer vertex i is joined
+// by a spoke to the inner vertex n+i. The inner vertices form a star polygon,
+// in which vertex n+i is adjacent to n + (i+k mod n).
+// It's required that n ≥ 3 and 1 ≤ k < n/2.
+//
+// The Petersen graph is GeneralizedPetersen(5, 2).
+func GeneralizedPetersen(n, k int) *Virtual {
+	switch {
+	case n < 0 || k < 0:
+		return nil
+	case n < 3 || k < 1 || 2*k >= n:
+		panic("no generalized Petersen graph with n=" + strconv.Itoa(n) + " k=" + strconv.Itoa(k))
+	case 2*n <= 0:
+		panic("too large n=" + strconv.Itoa(n))
+	}
+
+	g := generic0(2*n, func(v, w int) (edge bool) {
+		switch {
+		case v < n && w < n:
+			switch v - w {
+			case 1 - n, -1, 1, n - 1:
+				edge = true
+			}
+		case v >= n && w >= n:
+			switch v - w {
+			case k - n, -k, k, n - k:
+				edge = true
+			}
+		default:
+			edge = v-w == n || w-v == n
+		}
+		return
+	})
+
+	g.degree = func(v int) int { return 3 }
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		var list [3]int
+		if v < n {
+			list = [3]int{(v + n - 1) % n, (v + 1) % n, v + n}
+		} else {
+			i := v - n
+			list = [3]int{i, n + (i+n-k)%n, n + (i+k)%n}
+		}
+		return visitList(v, list[:], a, do)
+	}
+	return g
+}
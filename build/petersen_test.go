@@ -0,0 +1,29 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestGeneralizedPetersen(t *testing.T) {
+	if mess, diff := diff(GeneralizedPetersen(-1, 1), (*Virtual)(nil)); diff {
+		t.Errorf("GeneralizedPetersen %s", mess)
+	}
+
+	exp := "10 [{0 1} {0 4} {0 5} {1 2} {1 6} {2 3} {2 7} {3 4} {3 8} {4 9} {5 7} {5 8} {6 8} {6 9} {7 9}]"
+	if mess, diff := diff(GeneralizedPetersen(5, 2).String(), exp); diff {
+		t.Errorf("GeneralizedPetersen %s", mess)
+	}
+
+	// The prism is the generalized Petersen graph G(n, 1).
+	res := GeneralizedPetersen(6, 1)
+	if mess, diff := diff(graph.Equal(res, Circulant(6, 1).Match(Circulant(6, 1), AllEdges())), true); diff {
+		t.Errorf("GeneralizedPetersen %s", mess)
+	}
+
+	for n := 3; n < 12; n++ {
+		for k := 1; 2*k < n; k++ {
+			Consistent("GeneralizedPetersen", t, GeneralizedPetersen(n, k))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package build
+
+import "sort"
+
+// Power returns the k-th power of g: a graph with the same vertices as g,
+// in which there is an edge from v to w, v ≠ w, whenever there is a path
+// from v to w in g with at most k edges.
+// The edges of the new graph have zero cost, and k must be non-negative.
+//
+// The neighbors of a vertex are found by a breadth-first search in g
+// that stops at depth k.
+func (g *Virtual) Power(k int) *Virtual {
+	n := g.order
+	switch {
+	case k < 0:
+		return nil
+	case n <= 1 || k == 0:
+		return Empty(n)
+	case k == 1:
+		return g.AddCost(0)
+	}
+
+	// reach returns the vertices w ≠ v, w ≥ a, within distance k from v.
+	reach := func(v, a int) []int {
+		seen := map[int]bool{v: true}
+		var res []int
+		queue := []int{v}
+		for depth := 0; depth < k && len(queue) > 0; depth++ {
+			var next []int
+			for _, u := range queue {
+				g.visit(u, 0, func(w int, _ int64) (skip bool) {
+					if !seen[w] {
+						seen[w] = true
+						next = append(next, w)
+						if w >= a {
+							res = append(res, w)
+						}
+					}
+					return
+				})
+			}
+			queue = next
+		}
+		sort.Ints(res)
+		return res
+	}
+
+	res := generic0(n, func(v, w int) (edge bool) {
+		list := reach(v, w)
+		return len(list) > 0 && list[0] == w
+	})
+
+	res.degree = func(v int) int {
+		return len(reach(v, 0))
+	}
+
+	res.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		for _, w := range reach(v, a) {
+			if do(w, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return res
+}
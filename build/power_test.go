@@ -0,0 +1,42 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestPower(t *testing.T) {
+	if mess, diff := diff(Cycle(5).Power(-1), (*Virtual)(nil)); diff {
+		t.Errorf("Power %s", mess)
+	}
+
+	if mess, diff := diff(Cycle(5).Power(0).String(), "5 []"); diff {
+		t.Errorf("Power %s", mess)
+	}
+
+	if mess, diff := diff(Grid(1, 4).AddCost(1).Power(1).String(), "4 [{0 1} {1 2} {2 3}]"); diff {
+		t.Errorf("Power %s", mess)
+	}
+
+	if mess, diff := diff(Grid(1, 4).Power(2).String(), "4 [{0 1} {0 2} {1 2} {1 3} {2 3}]"); diff {
+		t.Errorf("Power %s", mess)
+	}
+
+	if mess, diff := diff(graph.Equal(Cycle(9).Power(3), Circulant(9, 1, 2, 3)), true); diff {
+		t.Errorf("Power %s", mess)
+	}
+
+	for _, g := range operands() {
+		for k := 0; k < 4; k++ {
+			res := g.Power(k)
+			dist := make([][]int64, g.Order())
+			for v := range dist {
+				_, dist[v] = graph.ShortestPaths(g.AddCost(1), v)
+			}
+			checkEdges("Power", t, res, func(v, w int) bool {
+				return dist[v][w] != -1 && dist[v][w] <= int64(k)
+			})
+			Consistent("Power", t, res)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package build
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Quotient returns the quotient graph of g with respect to the partition:
+// each block partition[i] is contracted to a single vertex i.
+// There is an edge from i to j, i ≠ j, if g has an edge from a vertex
+// in block i to a vertex in block j, and its cost is computed by calling
+// combine with the costs of all such edges in g. If combine is nil,
+// the edges have zero cost. Edges within a block are dropped.
+//
+// Each vertex of g must belong to exactly one block; empty blocks
+// become isolated vertices. The new graph stores the block of each vertex.
+func (g *Virtual) Quotient(partition [][]int, combine func(costs []int64) int64) *Virtual {
+	n, m := g.order, len(partition)
+	blocks := make([][]int, m)
+	block := make([]int, n)
+	for v := range block {
+		block[v] = -1
+	}
+	count := 0
+	for i, part := range partition {
+		blocks[i] = append([]int{}, part...)
+		for _, v := range part {
+			if v < 0 || v >= n || block[v] != -1 {
+				panic("not a partition: vertex " + strconv.Itoa(v))
+			}
+			block[v] = i
+			count++
+		}
+	}
+	if count != n {
+		panic("not a partition: " + strconv.Itoa(n-count) + " vertices missing")
+	}
+	switch m {
+	case 0:
+		return null
+	case 1:
+		return singleton()
+	}
+
+	// costs returns the costs of the edges from block i to block j.
+	costs := func(i, j int) (list []int64) {
+		for _, v := range blocks[i] {
+			g.visit(v, 0, func(w int, c int64) (skip bool) {
+				if block[w] == j {
+					list = append(list, c)
+				}
+				return
+			})
+		}
+		return
+	}
+
+	// neighbors returns the blocks j ≥ a adjacent to block i, in increasing
+	// order, and the costs of the edges to each of them if combine isn't nil.
+	neighbors := func(i, a int) ([]int, map[int][]int64) {
+		seen := make(map[int][]int64)
+		var list []int
+		for _, v := range blocks[i] {
+			g.visit(v, 0, func(w int, c int64) (skip bool) {
+				j := block[w]
+				if j == i || j < a {
+					return
+				}
+				if _, ok := seen[j]; !ok {
+					list = append(list, j)
+					seen[j] = nil
+				}
+				if combine != nil {
+					seen[j] = append(seen[j], c)
+				}
+				return
+			})
+		}
+		sort.Ints(list)
+		return list, seen
+	}
+
+	res := &Virtual{order: m}
+	res.edge = func(i, j int) bool {
+		if i == j {
+			return false
+		}
+		for _, v := range blocks[i] {
+			if g.visit(v, 0, func(w int, _ int64) bool { return block[w] == j }) {
+				return true
+			}
+		}
+		return false
+	}
+	res.cost = zero
+	if combine != nil {
+		res.cost = func(i, j int) int64 {
+			return combine(costs(i, j))
+		}
+	}
+	res.degree = func(i int) int {
+		list, _ := neighbors(i, 0)
+		return len(list)
+	}
+	res.visit = func(i int, a int, do func(j int, c int64) bool) (aborted bool) {
+		list, lists := neighbors(i, a)
+		for _, j := range list {
+			var c int64
+			if combine != nil {
+				c = combine(lists[j])
+			}
+			if do(j, c) {
+				return true
+			}
+		}
+		return
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestQuotient(t *testing.T) {
+	res := Grid(2, 2).Quotient([][]int{{0, 1}, {2, 3}}, nil)
+	if mess, diff := diff(res.String(), "2 [{0 1}]"); diff {
+		t.Errorf("Quotient %s", mess)
+	}
+	Consistent("Quotient", t, res)
+
+	count := func(costs []int64) int64 { return int64(len(costs)) }
+	res = Grid(3, 3).Quotient([][]int{{0, 1, 2}, {}, {3, 4, 5, 6, 7, 8}}, count)
+	if mess, diff := diff(res.String(), "3 [{0 2}:3]"); diff {
+		t.Errorf("Quotient %s", mess)
+	}
+	Consistent("Quotient", t, res)
+
+	// Contracting the blocks of K3 ⨯ K3 by the first coordinate gives K3.
+	sum := func(costs []int64) (s int64) {
+		for _, c := range costs {
+			s += c
+		}
+		return
+	}
+	res = Kn(3).Cartesian(Kn(3)).AddCost(2).Quotient([][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}}, sum)
+	if mess, diff := diff(res.String(), "3 [{0 1}:6 {0 2}:6 {1 2}:6]"); diff {
+		t.Errorf("Quotient %s", mess)
+	}
+	Consistent("Quotient", t, res)
+
+	// Singleton blocks give the original graph.
+	for _, g := range operands() {
+		n := g.Order()
+		partition := make([][]int, n)
+		for v := range partition {
+			partition[v] = []int{v}
+		}
+		res := g.Quotient(partition, nil)
+		if mess, diff := diff(graph.Equal(res, g), true); diff {
+			t.Errorf("Quotient %s", mess)
+		}
+		Consistent("Quotient", t, res)
+	}
+
+	// A directed graph: the block {0, 1} of DeBruijn(2, 2).
+	res = DeBruijn(2, 2).Quotient([][]int{{2}, {0, 1}, {3}}, count)
+	if mess, diff := diff(res.String(), "3 [(0 1):2 (1 0):1 (1 2):1 (2 0):1]"); diff {
+		t.Errorf("Quotient %s", mess)
+	}
+	Consistent("Quotient", t, res)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Quotient: no panic for invalid partition")
+		}
+	}()
+	Kn(3).Quotient([][]int{{0, 1}}, nil)
+}
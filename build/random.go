@@ -0,0 +1,381 @@
+package build
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// ErdosRenyi returns a virtual random graph with n vertices in which
+// each edge {v, w}, v ≠ w, is included with probability p,
+// independently of all other edges.
+// The graph is determined by the seed.
+//
+// The edges are generated by the method of Batagelj and Brandes
+// in time proportional to the size of the graph.
+func ErdosRenyi(n int, p float64, seed int64) *Virtual {
+	switch {
+	case n < 0:
+		return nil
+	case !(0 <= p && p <= 1):
+		panic("p=" + strconv.FormatFloat(p, 'g', -1, 64) + " out of range")
+	case p == 1 && n > 1:
+		return Kn(n)
+	}
+	// The probability of skipping a non-edge is q = 1 - p.
+	logq := math.Log1p(-p)
+	if n <= 1 || logq == 0 {
+		return Empty(n)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	var edges [][2]int
+	// Skip a geometrically distributed number of non-edges
+	// in the list {1, 0}, {2, 0}, {2, 1}, {3, 0}, ...
+	for v, w := 1, -1; v < n; {
+		skip := math.Log(1-rnd.Float64()) / logq
+		if skip >= float64(n)*float64(n) {
+			break
+		}
+		w += 1 + int(skip)
+		for w >= v && v < n {
+			w -= v
+			v++
+		}
+		if v < n {
+			edges = append(edges, [2]int{v, w})
+		}
+	}
+	return cached(n, edges)
+}
+
+// Gnm returns a virtual random graph with n vertices and m edges,
+// chosen uniformly at random among all such graphs.
+// The graph is determined by the seed.
+func Gnm(n, m int, seed int64) *Virtual {
+	max := n * (n - 1) / 2
+	switch {
+	case n < 0 || m < 0:
+		return nil
+	case m > max:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	case m == 0:
+		return Empty(n)
+	case m == max:
+		return Kn(n)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	// Choose the edges, or the non-edges if they are fewer.
+	k := m
+	if 2*m > max {
+		k = max - m
+	}
+	chosen := make(map[[2]int]bool, k)
+	for len(chosen) < k {
+		v, w := rnd.Intn(n), rnd.Intn(n)
+		if v == w {
+			continue
+		}
+		if v < w {
+			v, w = w, v
+		}
+		chosen[[2]int{v, w}] = true
+	}
+	edges := make([][2]int, 0, m)
+	if k == m {
+		for e := range chosen {
+			edges = append(edges, e)
+		}
+	} else {
+		for v := 1; v < n; v++ {
+			for w := 0; w < v; w++ {
+				if e := [2]int{v, w}; !chosen[e] {
+					edges = append(edges, e)
+				}
+			}
+		}
+	}
+	return cached(n, edges)
+}
+
+// BarabasiAlbert returns a virtual random graph with n vertices grown by
+// preferential attachment. The graph starts with m isolated vertices,
+// and each new vertex is joined to m distinct existing vertices, chosen
+// with probability proportional to their degree.
+// The graph is determined by the seed.
+func BarabasiAlbert(n, m int, seed int64) *Virtual {
+	switch {
+	case n < 0 || m < 0:
+		return nil
+	case m == 0:
+		return Empty(n)
+	case m >= n:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, 0, m*(n-m))
+	// Each vertex occurs once in ends for each edge it belongs to.
+	ends := make([]int, 0, 2*m*(n-m))
+	targets := make([]int, m)
+	for w := range targets {
+		targets[w] = w
+	}
+	chosen := make(map[int]bool, m)
+	for v := m; v < n; v++ {
+		for _, w := range targets {
+			edges = append(edges, [2]int{v, w})
+			ends = append(ends, v, w)
+		}
+		for w := range chosen {
+			delete(chosen, w)
+		}
+		targets = targets[:0]
+		for len(targets) < m {
+			w := ends[rnd.Intn(len(ends))]
+			if !chosen[w] {
+				chosen[w] = true
+				targets = append(targets, w)
+			}
+		}
+	}
+	return cached(n, edges)
+}
+
+// WattsStrogatz returns a virtual small-world random graph with n vertices.
+// The graph starts as a ring in which each vertex is joined to the k
+// nearest vertices on each side. Then each edge {v, v+j}, 1 ≤ j ≤ k,
+// is rewired with probability p to {v, w}, where w is chosen uniformly
+// at random among the vertices not adjacent to v.
+// The graph is determined by the seed.
+func WattsStrogatz(n, k int, p float64, seed int64) *Virtual {
+	switch {
+	case n < 0 || k < 0:
+		return nil
+	case 2*k >= n && k > 0:
+		panic("too large k=" + strconv.Itoa(k) + " n=" + strconv.Itoa(n))
+	case !(0 <= p && p <= 1):
+		panic("p=" + strconv.FormatFloat(p, 'g', -1, 64) + " out of range")
+	case k == 0:
+		return Empty(n)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	adj := make([]map[int]bool, n)
+	for v := range adj {
+		adj[v] = make(map[int]bool, 2*k)
+	}
+	for v := 0; v < n; v++ {
+		for j := 1; j <= k; j++ {
+			w := (v + j) % n
+			adj[v][w], adj[w][v] = true, true
+		}
+	}
+	for j := 1; j <= k; j++ {
+		for v := 0; v < n; v++ {
+			w := (v + j) % n
+			if rnd.Float64() >= p || len(adj[v]) == n-1 || !adj[v][w] {
+				continue
+			}
+			x := rnd.Intn(n)
+			for x == v || adj[v][x] {
+				x = rnd.Intn(n)
+			}
+			delete(adj[v], w)
+			delete(adj[w], v)
+			adj[v][x], adj[x][v] = true, true
+		}
+	}
+	var edges [][2]int
+	for v, neighbors := range adj {
+		for w := range neighbors {
+			if v < w {
+				edges = append(edges, [2]int{v, w})
+			}
+		}
+	}
+	return cached(n, edges)
+}
+
+// RandomRegular returns a virtual random d-regular graph with n vertices,
+// a graph in which every vertex has d neighbors. The product n⋅d must be
+// even and d must be smaller than n.
+// The graph is determined by the seed.
+//
+// The graph is generated by repeatedly pairing up the endpoints of the
+// edges at random, keeping only pairs that don't form self-loops or
+// multiple edges, and starting over if no such pair remains.
+// The distribution is approximately uniform for small values of d.
+func RandomRegular(n, d int, seed int64) *Virtual {
+	switch {
+	case n < 0 || d < 0:
+		return nil
+	case d >= n && d > 0 || n*d%2 != 0:
+		panic("no " + strconv.Itoa(d) + "-regular graph with n=" + strconv.Itoa(n))
+	case d == 0:
+		return Empty(n)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	degrees := make([]int, n)
+	for v := range degrees {
+		degrees[v] = d
+	}
+	for {
+		if edges, ok := pairStubs(degrees, rnd, true); ok {
+			return cached(n, edges)
+		}
+	}
+}
+
+// Configuration returns a virtual random graph in which vertex v has
+// at most degrees[v] neighbors. The endpoints of the edges are paired
+// up uniformly at random, and self-loops and multiple edges are then
+// erased; the degrees are exact only if this removes no edges.
+// The sum of the degrees must be even.
+// The graph is determined by the seed.
+func Configuration(degrees []int, seed int64) *Virtual {
+	sum := 0
+	for _, d := range degrees {
+		if d < 0 {
+			return nil
+		}
+		sum += d
+	}
+	if sum%2 != 0 {
+		panic("odd degree sum " + strconv.Itoa(sum))
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	edges, _ := pairStubs(degrees, rnd, false)
+	return cached(len(degrees), edges)
+}
+
+// pairStubs pairs up the endpoints of the edges, where vertex v occurs
+// degrees[v] times. If simple is false, the endpoints are paired uniformly
+// at random, and self-loops are dropped. If simple is true, the pairs are
+// chosen among those that form neither self-loops nor multiple edges,
+// and ok is false if the pairing gets stuck.
+func pairStubs(degrees []int, rnd *rand.Rand, simple bool) (edges [][2]int, ok bool) {
+	var stubs []int
+	for v, d := range degrees {
+		for i := 0; i < d; i++ {
+			stubs = append(stubs, v)
+		}
+	}
+	if !simple {
+		rnd.Shuffle(len(stubs), func(i, j int) { stubs[i], stubs[j] = stubs[j], stubs[i] })
+		for i := 0; i+1 < len(stubs); i += 2 {
+			if v, w := stubs[i], stubs[i+1]; v != w {
+				edges = append(edges, [2]int{v, w})
+			}
+		}
+		return edges, true
+	}
+
+	seen := make(map[[2]int]bool)
+	key := func(v, w int) [2]int {
+		if v < w {
+			return [2]int{v, w}
+		}
+		return [2]int{w, v}
+	}
+	for len(stubs) > 0 {
+		// Pair the stubs in random order and put back the ones that fail.
+		rnd.Shuffle(len(stubs), func(i, j int) { stubs[i], stubs[j] = stubs[j], stubs[i] })
+		var rest []int
+		for i := 0; i+1 < len(stubs); i += 2 {
+			v, w := stubs[i], stubs[i+1]
+			if e := key(v, w); v != w && !seen[e] {
+				seen[e] = true
+				edges = append(edges, [2]int{v, w})
+			} else {
+				rest = append(rest, v, w)
+			}
+		}
+		if len(rest) == len(stubs) && !suitable(rest, seen, key) {
+			return nil, false
+		}
+		stubs = rest
+	}
+	return edges, true
+}
+
+// suitable tells if two stubs in the list can be paired.
+func suitable(stubs []int, seen map[[2]int]bool, key func(v, w int) [2]int) bool {
+	for i, v := range stubs {
+		for _, w := range stubs[i+1:] {
+			if v != w && !seen[key(v, w)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cached returns a virtual graph with n vertices and the edges {v, w}
+// in the list, which must not contain self-loops. Duplicate edges are
+// removed. The neighbors of each vertex are stored in a sorted list,
+// and all lists are kept in a single slice.
+func cached(n int, edges [][2]int) *Virtual {
+	switch {
+	case n == 0:
+		return null
+	case n == 1:
+		return singleton()
+	case n > math.MaxInt32:
+		panic("too large n=" + strconv.Itoa(n))
+	}
+	// Neighbors of v are stored in neighbors[start[v]:start[v+1]].
+	start := make([]int, n+1)
+	for _, e := range edges {
+		start[e[0]+1]++
+		start[e[1]+1]++
+	}
+	for v := 0; v < n; v++ {
+		start[v+1] += start[v]
+	}
+	neighbors := make([]int32, start[n])
+	next := append([]int{}, start[:n]...)
+	for _, e := range edges {
+		v, w := e[0], e[1]
+		neighbors[next[v]] = int32(w)
+		neighbors[next[w]] = int32(v)
+		next[v]++
+		next[w]++
+	}
+	// Sort and remove duplicates, compacting the slice.
+	k := 0
+	for v := 0; v < n; v++ {
+		list := neighbors[start[v]:start[v+1]]
+		sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+		start[v] = k
+		for i, w := range list {
+			if i == 0 || w != list[i-1] {
+				neighbors[k] = w
+				k++
+			}
+		}
+	}
+	start[n] = k
+	neighbors = neighbors[:k:k]
+
+	search := func(v, a int) int {
+		lo, hi := start[v], start[v+1]
+		return lo + sort.Search(hi-lo, func(i int) bool { return int(neighbors[lo+i]) >= a })
+	}
+	g := &Virtual{
+		order: n,
+		cost:  zero,
+		edge: func(v, w int) bool {
+			i := search(v, w)
+			return i < start[v+1] && int(neighbors[i]) == w
+		},
+		degree: func(v int) int { return start[v+1] - start[v] },
+	}
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		for i := search(v, a); i < start[v+1]; i++ {
+			if do(int(neighbors[i]), 0) {
+				return true
+			}
+		}
+		return
+	}
+	return g
+}
@@ -0,0 +1,156 @@
+package build
+
+import (
+	"testing"
+)
+
+// Check that g is reproducible and has the expected number of edges.
+func checkRandom(mess string, t *testing.T, g, h *Virtual, edges int) {
+	Consistent(mess, t, g)
+	if mess1, diff := diff(g.String(), h.String()); diff {
+		t.Errorf("%s: not reproducible: %s", mess, mess1)
+	}
+	if edges < 0 {
+		return
+	}
+	m := 0
+	for v := 0; v < g.Order(); v++ {
+		m += g.degree(v)
+	}
+	if mess1, diff := diff(m, 2*edges); diff {
+		t.Errorf("%s: edges %s", mess, mess1)
+	}
+}
+
+func TestErdosRenyi(t *testing.T) {
+	if ErdosRenyi(-1, 0.5, 1) != nil {
+		t.Errorf("ErdosRenyi(-1, 0.5, 1) should be nil")
+	}
+	if mess, diff := diff(ErdosRenyi(3, 0, 1).String(), "3 []"); diff {
+		t.Errorf("ErdosRenyi %s", mess)
+	}
+	if mess, diff := diff(ErdosRenyi(3, 1, 1).String(), "3 [{0 1} {0 2} {1 2}]"); diff {
+		t.Errorf("ErdosRenyi %s", mess)
+	}
+	for n := 0; n < 10; n++ {
+		for seed := int64(0); seed < 5; seed++ {
+			checkRandom("ErdosRenyi", t, ErdosRenyi(n, 0.3, seed), ErdosRenyi(n, 0.3, seed), -1)
+		}
+	}
+
+	// The number of edges is binomially distributed with mean 4950⋅0.1
+	// and standard deviation 21.
+	g := ErdosRenyi(100, 0.1, 1)
+	m := 0
+	for v := 0; v < 100; v++ {
+		m += g.degree(v)
+	}
+	if m/2 < 495-5*21 || m/2 > 495+5*21 {
+		t.Errorf("ErdosRenyi(100, 0.1): %d edges", m/2)
+	}
+
+	// Here log(1-p) rounds to zero.
+	if mess, diff := diff(ErdosRenyi(100, 1e-17, 1).String(), "100 []"); diff {
+		t.Errorf("ErdosRenyi %s", mess)
+	}
+	checkRandom("ErdosRenyi", t, ErdosRenyi(100, 1e-10, 1), ErdosRenyi(100, 1e-10, 1), -1)
+}
+
+func TestGnm(t *testing.T) {
+	if Gnm(3, -1, 1) != nil {
+		t.Errorf("Gnm(3, -1, 1) should be nil")
+	}
+	if mess, diff := diff(Gnm(3, 3, 1).String(), "3 [{0 1} {0 2} {1 2}]"); diff {
+		t.Errorf("Gnm %s", mess)
+	}
+	for n := 0; n < 10; n++ {
+		for m := 0; m <= n*(n-1)/2; m++ {
+			checkRandom("Gnm", t, Gnm(n, m, int64(m)), Gnm(n, m, int64(m)), m)
+		}
+	}
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	if BarabasiAlbert(-1, 1, 1) != nil {
+		t.Errorf("BarabasiAlbert(-1, 1, 1) should be nil")
+	}
+	if mess, diff := diff(BarabasiAlbert(3, 1, 1).Degree(0)+BarabasiAlbert(3, 1, 1).Degree(1), 3); diff {
+		t.Errorf("BarabasiAlbert %s", mess)
+	}
+	for n := 1; n < 20; n++ {
+		for m := 0; m < n && m < 4; m++ {
+			checkRandom("BarabasiAlbert", t, BarabasiAlbert(n, m, 7), BarabasiAlbert(n, m, 7), m*(n-m))
+		}
+	}
+	if mess, diff := diff(BarabasiAlbert(0, 0, 1).String(), "0 []"); diff {
+		t.Errorf("BarabasiAlbert %s", mess)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("BarabasiAlbert(0, 1, 1): no panic")
+		}
+	}()
+	BarabasiAlbert(0, 1, 1)
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	if WattsStrogatz(-1, 1, 0.5, 1) != nil {
+		t.Errorf("WattsStrogatz(-1, 1, 0.5, 1) should be nil")
+	}
+	if mess, diff := diff(WattsStrogatz(5, 1, 0, 1).String(), Cycle(5).String()); diff {
+		t.Errorf("WattsStrogatz %s", mess)
+	}
+	for n := 0; n < 20; n++ {
+		for k := 0; 2*k < n; k++ {
+			for _, p := range []float64{0, 0.2, 1} {
+				checkRandom("WattsStrogatz", t, WattsStrogatz(n, k, p, 3), WattsStrogatz(n, k, p, 3), n*k)
+			}
+		}
+	}
+}
+
+func TestRandomRegular(t *testing.T) {
+	if RandomRegular(-1, 1, 1) != nil {
+		t.Errorf("RandomRegular(-1, 1, 1) should be nil")
+	}
+	for n := 0; n < 20; n++ {
+		for d := 0; d < n; d++ {
+			if n*d%2 != 0 {
+				continue
+			}
+			g := RandomRegular(n, d, int64(n+d))
+			checkRandom("RandomRegular", t, g, RandomRegular(n, d, int64(n+d)), n*d/2)
+			for v := 0; v < n; v++ {
+				if g.Degree(v) != d {
+					t.Errorf("RandomRegular(%d, %d): degree(%d) = %d", n, d, v, g.Degree(v))
+				}
+			}
+		}
+	}
+}
+
+func TestConfiguration(t *testing.T) {
+	if Configuration([]int{1, -1}, 1) != nil {
+		t.Errorf("Configuration([1 -1], 1) should be nil")
+	}
+	if mess, diff := diff(Configuration([]int{1, 1}, 1).String(), "2 [{0 1}]"); diff {
+		t.Errorf("Configuration %s", mess)
+	}
+	degrees := []int{3, 3, 2, 2, 2, 1, 1, 0}
+	for seed := int64(0); seed < 20; seed++ {
+		g := Configuration(degrees, seed)
+		checkRandom("Configuration", t, g, Configuration(degrees, seed), -1)
+		for v, d := range degrees {
+			if g.Degree(v) > d {
+				t.Errorf("Configuration: degree(%d) = %d > %d", v, g.Degree(v), d)
+			}
+		}
+	}
+}
+
+func BenchmarkErdosRenyi(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = ErdosRenyi(10000, 0.001, int64(i))
+	}
+}
@@ -0,0 +1,24 @@
+package build
+
+// Reverse returns the transpose of g: a graph with the same vertices as g,
+// in which each edge (v, w) of g has been replaced by an edge (w, v)
+// with the same cost.
+//
+// Unlike graph.Transpose, Reverse doesn't store any edges.
+// Instead, the neighbors of v are found by checking for each vertex w
+// if there is an edge (w, v) in g, which takes time proportional to
+// the number of vertices.
+func (g *Virtual) Reverse() *Virtual {
+	n := g.order
+	switch n {
+	case 0:
+		return null
+	case 1:
+		return singleton()
+	}
+	return generic(n, func(v, w int) int64 {
+		return g.cost(w, v)
+	}, func(v, w int) bool {
+		return g.edge(w, v)
+	})
+}
@@ -0,0 +1,22 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	res := DeBruijn(2, 2).Reverse()
+	if mess, diff := diff(res.String(), "4 [(0 2) (1 0) {1 2} (2 3) (3 1)]"); diff {
+		t.Errorf("Reverse %s", mess)
+	}
+
+	for _, g := range operands() {
+		g = g.AddCostFunc(func(v, w int) int64 { return int64(10*v + w) })
+		res := g.Reverse()
+		if mess, diff := diff(graph.Equal(res, graph.Transpose(g)), true); diff {
+			t.Errorf("Reverse %s", mess)
+		}
+		Consistent("Reverse", t, res)
+	}
+}
@@ -0,0 +1,68 @@
+package build
+
+import "strconv"
+
+// StrongProduct returns the strong product of g1 and g2:
+// a graph whose vertices correspond to ordered pairs (v1, v2),
+// where v1 and v2 are vertices in g1 and g2, respectively.
+// The vertices (v1, v2) and (w1, w2) are connected by an edge if they are
+// connected in either the cartesian product or the tensor product:
+// v1 = w1 or {v1, w1} ∊ g1, and v2 = w2 or {v2, w2} ∊ g2.
+//
+// In the new graph, vertex (v1, v2) gets index n⋅v1 + v2, where n = g2.Order(),
+// and index i corresponds to the vertice (i/n, i%n).
+func (g1 *Virtual) StrongProduct(g2 *Virtual) *Virtual {
+	m, n := g1.Order(), g2.Order()
+	switch {
+	case m == 0 || n == 0:
+		return null
+	case m*n/m != n:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+
+	g := generic0(m*n, func(v, w int) (edge bool) {
+		v1, v2 := v/n, v%n
+		w1, w2 := w/n, w%n
+		return (v1 == w1 || g1.edge(v1, w1)) && (v2 == w2 || g2.edge(v2, w2))
+	})
+
+	g.degree = func(v int) (deg int) {
+		return (g1.degree(v/n)+1)*(g2.degree(v%n)+1) - 1
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		v1, v2 := v/n, v%n
+		a1, a2 := a/n, a%n
+		return visitClosed(g1, v1, a1, func(w1 int) (skip bool) {
+			b := 0
+			if w1 == a1 {
+				b = a2
+			}
+			return visitClosed(g2, v2, b, func(w2 int) (skip bool) {
+				if w := n*w1 + w2; w != v {
+					return do(w, 0)
+				}
+				return
+			})
+		})
+	}
+	return g
+}
+
+// visitClosed visits the closed neighborhood of v in g, the neighbors
+// of v and v itself, for which w ≥ a in numerical order.
+func visitClosed(g *Virtual, v int, a int, do func(w int) bool) (aborted bool) {
+	self := v < a // tells if v has been visited, or should be skipped
+	if g.visit(v, a, func(w int, _ int64) (skip bool) {
+		if !self && w > v {
+			self = true
+			if do(v) {
+				return true
+			}
+		}
+		return do(w)
+	}) {
+		return true
+	}
+	return !self && do(v)
+}
@@ -0,0 +1,48 @@
+package build
+
+import "testing"
+
+// operands is a list of small graphs used to test graph operators.
+func operands() []*Virtual {
+	return []*Virtual{
+		null, Kn(1), Kn(2), Empty(2), Kn(3), Cycle(4), Grid(2, 3),
+		Kmn(1, 3), Tree(2, 3), DeBruijn(2, 2),
+	}
+}
+
+// checkEdges checks that the edges of g are given by edge.
+func checkEdges(mess string, t *testing.T, g *Virtual, edge func(v, w int) bool) {
+	n := g.Order()
+	for v := 0; v < n; v++ {
+		for w := 0; w < n; w++ {
+			if exp := v != w && edge(v, w); g.Edge(v, w) != exp {
+				t.Errorf("%s: Edge(%d, %d) = %t; want %t", mess, v, w, !exp, exp)
+			}
+		}
+	}
+}
+
+func TestStrongProduct(t *testing.T) {
+	res := Kn(2).StrongProduct(Kn(2))
+	if mess, diff := diff(res.String(), "4 [{0 1} {0 2} {0 3} {1 2} {1 3} {2 3}]"); diff {
+		t.Errorf("StrongProduct %s", mess)
+	}
+
+	res = Grid(1, 3).StrongProduct(Grid(1, 2))
+	exp := "6 [{0 1} {0 2} {0 3} {1 2} {1 3} {2 3} {2 4} {2 5} {3 4} {3 5} {4 5}]"
+	if mess, diff := diff(res.String(), exp); diff {
+		t.Errorf("StrongProduct %s", mess)
+	}
+
+	for _, g1 := range operands() {
+		for _, g2 := range operands() {
+			res := g1.StrongProduct(g2)
+			n := g2.Order()
+			checkEdges("StrongProduct", t, res, func(v, w int) bool {
+				v1, v2, w1, w2 := v/n, v%n, w/n, w%n
+				return (v1 == w1 || g1.Edge(v1, w1)) && (v2 == w2 || g2.Edge(v2, w2))
+			})
+			Consistent("StrongProduct", t, res)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package build
+
+import "strconv"
+
+// Torus returns a virtual torus graph: a grid, numbered as in Grid,
+// with wrap-around edges. Point (x, y) is adjacent to the points
+// (x±1 mod m, y) and (x, y±1 mod n).
+// No wrap-around edges are added in a dimension of size less than 3,
+// since they would be duplicates or self-loops.
+//
+// Point (x, y) gets index nx + y, and index i corresponds to the point (i/n, i%n).
+func Torus(m, n int) *Virtual {
+	switch {
+	case m < 0 || n < 0:
+		return nil
+	case m == 0 || n == 0:
+		return null
+	case m <= 2 && n <= 2:
+		return Grid(m, n)
+	case m*n/m != n:
+		panic("too large m=" + strconv.Itoa(m) + " n=" + strconv.Itoa(n))
+	}
+
+	// adjacent tells if the coordinates x and y in 0..size-1 are adjacent.
+	adjacent := func(x, y, size int) bool {
+		switch x - y {
+		case 1 - size, -1, 1, size - 1:
+			return true
+		}
+		return false
+	}
+	g := generic0(m*n, func(v, w int) (edge bool) {
+		x1, y1, x2, y2 := v/n, v%n, w/n, w%n
+		return x1 == x2 && adjacent(y1, y2, n) || y1 == y2 && adjacent(x1, x2, m)
+	})
+
+	deg := min(m-1, 2) + min(n-1, 2)
+	g.degree = func(v int) int { return deg }
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		x, y := v/n, v%n
+		list := [4]int{
+			(x+m-1)%m*n + y, // up
+			x*n + (y+n-1)%n, // left
+			x*n + (y+1)%n,   // right
+			(x+1)%m*n + y,   // down
+		}
+		return visitList(v, list[:], a, do)
+	}
+	return g
+}
@@ -0,0 +1,35 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestTorus(t *testing.T) {
+	if mess, diff := diff(Torus(-1, 2), (*Virtual)(nil)); diff {
+		t.Errorf("Torus %s", mess)
+	}
+
+	if mess, diff := diff(Torus(0, 3).String(), "0 []"); diff {
+		t.Errorf("Torus %s", mess)
+	}
+
+	if mess, diff := diff(Torus(1, 3).String(), "3 [{0 1} {0 2} {1 2}]"); diff {
+		t.Errorf("Torus %s", mess)
+	}
+
+	exp := "9 [{0 1} {0 2} {0 3} {0 6} {1 2} {1 4} {1 7} {2 5} {2 8} {3 4} {3 5} {3 6} {4 5} {4 7} {5 8} {6 7} {6 8} {7 8}]"
+	if mess, diff := diff(Torus(3, 3).String(), exp); diff {
+		t.Errorf("Torus %s", mess)
+	}
+
+	for m := 0; m < 6; m++ {
+		for n := 0; n < 6; n++ {
+			res := Torus(m, n)
+			if mess, diff := diff(graph.Equal(res, Cycle(m).Cartesian(Cycle(n))), true); diff {
+				t.Errorf("Torus(%d, %d) %s", m, n, mess)
+			}
+			Consistent("Torus", t, res)
+		}
+	}
+}
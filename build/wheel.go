@@ -0,0 +1,62 @@
+package build
+
+import "strconv"
+
+// Wheel returns a virtual wheel graph with n+1 vertices:
+// a hub 0 joined by a spoke to each vertex in the cycle 1, 2,… , n.
+// The rim cycle has the edges {1, 2}, {2, 3},… , {n-1, n}, {n, 1}.
+func Wheel(n int) *Virtual {
+	switch {
+	case n < 0:
+		return nil
+	case n <= 3:
+		return Kn(n + 1)
+	case n+1 <= 0:
+		panic("too large n=" + strconv.Itoa(n))
+	}
+
+	g := generic0(n+1, func(v, w int) (edge bool) {
+		if v == 0 || w == 0 {
+			return true
+		}
+		switch v - w {
+		case 1 - n, -1, 1, n - 1:
+			edge = true
+		}
+		return
+	})
+
+	g.degree = func(v int) int {
+		if v == 0 {
+			return n
+		}
+		return 3
+	}
+
+	g.visit = func(v int, a int, do func(w int, c int64) bool) (aborted bool) {
+		if v == 0 {
+			for w := max(a, 1); w <= n; w++ {
+				if do(w, 0) {
+					return true
+				}
+			}
+			return
+		}
+		var w [3]int
+		switch v {
+		case 1:
+			w = [3]int{0, 2, n}
+		case n:
+			w = [3]int{0, 1, n - 1}
+		default:
+			w = [3]int{0, v - 1, v + 1}
+		}
+		for _, w := range w {
+			if w >= a && do(w, 0) {
+				return true
+			}
+		}
+		return
+	}
+	return g
+}
@@ -0,0 +1,36 @@
+package build
+
+import (
+	"github.com/yourbasic/graph"
+	"testing"
+)
+
+func TestWheel(t *testing.T) {
+	if mess, diff := diff(Wheel(-1), (*Virtual)(nil)); diff {
+		t.Errorf("Wheel %s", mess)
+	}
+
+	if mess, diff := diff(Wheel(0).String(), "1 []"); diff {
+		t.Errorf("Wheel %s", mess)
+	}
+
+	if mess, diff := diff(Wheel(2).String(), "3 [{0 1} {0 2} {1 2}]"); diff {
+		t.Errorf("Wheel %s", mess)
+	}
+
+	exp := "5 [{0 1} {0 2} {0 3} {0 4} {1 2} {1 4} {2 3} {3 4}]"
+	if mess, diff := diff(Wheel(4).String(), exp); diff {
+		t.Errorf("Wheel %s", mess)
+	}
+
+	for n := 3; n < 10; n++ {
+		exp := Empty(1).Join(Cycle(n), AllEdges())
+		if mess, diff := diff(graph.Equal(Wheel(n), exp), true); diff {
+			t.Errorf("Wheel(%d) %s", n, mess)
+		}
+	}
+
+	for n := 0; n < 10; n++ {
+		Consistent("Wheel", t, Wheel(n))
+	}
+}
@@ -0,0 +1,186 @@
+package graph
+
+import "strconv"
+
+// DuplicatePolicy tells how an ImmutableBuilder handles multiple edges
+// from v to w.
+type DuplicatePolicy int
+
+const (
+	KeepDuplicates DuplicatePolicy = iota // Keep all edges, as in a multigraph.
+	KeepFirst                             // Keep the first edge added.
+	MinCost                               // Keep one edge with the minimum cost.
+	SumCosts                              // Keep one edge with the sum of the costs.
+)
+
+// ImmutableBuilder builds an Immutable graph from a list of edges,
+// without the overhead of a Mutable graph. The edges are kept in flat
+// slices, using eight bytes per edge and another eight bytes for the cost
+// if some edge has non-zero cost, and the graph is built using counting
+// sort in time O(|E| + |V|).
+//
+// The zero value of an ImmutableBuilder isn't usable;
+// use NewImmutableBuilder.
+type ImmutableBuilder struct {
+	// Duplicates tells how multiple edges from v to w are handled
+	// by Finish. The default is KeepDuplicates.
+	Duplicates DuplicatePolicy
+
+	n        int
+	from, to []int32
+	cost     []int64 // nil if all costs are zero
+}
+
+// NewImmutableBuilder returns a builder for a graph with n vertices,
+// numbered from 0 to n-1, and no edges.
+func NewImmutableBuilder(n int) *ImmutableBuilder {
+	if n < 0 {
+		panic("negative number of vertices: " + strconv.Itoa(n))
+	}
+	checkOrder(n)
+	return &ImmutableBuilder{n: n}
+}
+
+// AddEdge adds a directed edge from v to w with zero cost.
+func (b *ImmutableBuilder) AddEdge(v, w int) {
+	b.AddCost(v, w, 0)
+}
+
+// AddCost adds a directed edge from v to w with cost c.
+func (b *ImmutableBuilder) AddCost(v, w int, c int64) {
+	if v < 0 || v >= b.n {
+		panic("vertex out of range: " + strconv.Itoa(v))
+	}
+	if w < 0 || w >= b.n {
+		panic("vertex out of range: " + strconv.Itoa(w))
+	}
+	b.from = append(b.from, int32(v))
+	b.to = append(b.to, int32(w))
+	if c != 0 && b.cost == nil {
+		b.cost = make([]int64, len(b.to)-1, cap(b.to))
+	}
+	if b.cost != nil {
+		b.cost = append(b.cost, c)
+	}
+}
+
+// AddBoth adds edges with zero cost between v and w.
+// If v = w, a single loop is added.
+func (b *ImmutableBuilder) AddBoth(v, w int) {
+	b.AddBothCost(v, w, 0)
+}
+
+// AddBothCost adds edges with cost c between v and w.
+// If v = w, a single loop is added.
+func (b *ImmutableBuilder) AddBothCost(v, w int, c int64) {
+	b.AddCost(v, w, c)
+	if v != w {
+		b.AddCost(w, v, c)
+	}
+}
+
+// Finish returns the graph built from the added edges,
+// handling multiple edges as specified by b.Duplicates.
+// The builder is left without edges and can be reused.
+func (b *ImmutableBuilder) Finish() *Immutable {
+	n, m := b.n, len(b.to)
+
+	// Two passes of counting sort, first by w and then by v, sort the edges
+	// by (v, w) and keep the edges from v to w in the order they were added.
+	byW := make([]int, n+1)
+	for _, w := range b.to {
+		byW[w+1]++
+	}
+	for w := 0; w < n; w++ {
+		byW[w+1] += byW[w]
+	}
+	next := make([]int, n)
+	copy(next, byW)
+	from := make([]int32, m)
+	var cost []int64
+	if b.cost != nil {
+		cost = make([]int64, m)
+	}
+	for i, w := range b.to {
+		j := next[w]
+		next[w]++
+		from[j] = b.from[i]
+		if cost != nil {
+			cost[j] = b.cost[i]
+		}
+	}
+	b.from, b.to, b.cost = nil, nil, nil
+
+	h := &Immutable{
+		offset: make([]int, n+1),
+		vertex: make([]int32, m),
+	}
+	if cost != nil {
+		h.cost = make([]int64, m)
+	}
+	for _, v := range from {
+		h.offset[v+1]++
+	}
+	for v := 0; v < n; v++ {
+		h.offset[v+1] += h.offset[v]
+	}
+	copy(next, h.offset)
+	for w := 0; w < n; w++ {
+		for i := byW[w]; i < byW[w+1]; i++ {
+			v := from[i]
+			j := next[v]
+			next[v]++
+			h.vertex[j] = int32(w)
+			if cost != nil {
+				h.cost[j] = cost[i]
+			}
+		}
+	}
+
+	if b.Duplicates == KeepDuplicates {
+		h.sortLists()
+	} else {
+		h.merge(b.Duplicates)
+	}
+	h.stats = h.computeStats()
+	if h.stats.Weighted == 0 {
+		h.cost = nil
+	}
+	return h
+}
+
+// merge replaces multiple edges from v to w, which must be adjacent
+// in the list of v, by a single edge, as specified by policy.
+func (g *Immutable) merge(policy DuplicatePolicy) {
+	k := 0
+	for v := 0; v < g.Order(); v++ {
+		start, end := g.offset[v], g.offset[v+1]
+		g.offset[v] = k
+		for i := start; i < end; i++ {
+			if k > g.offset[v] && g.vertex[k-1] == g.vertex[i] {
+				if g.cost == nil {
+					continue
+				}
+				switch policy {
+				case MinCost:
+					if g.cost[i] < g.cost[k-1] {
+						g.cost[k-1] = g.cost[i]
+					}
+				case SumCosts:
+					g.cost[k-1] += g.cost[i]
+				}
+				continue
+			}
+			g.vertex[k] = g.vertex[i]
+			if g.cost != nil {
+				g.cost[k] = g.cost[i]
+			}
+			k++
+		}
+	}
+	g.offset[g.Order()] = k
+	g.vertex = g.vertex[:k:k]
+	if g.cost != nil {
+		g.cost = g.cost[:k:k]
+	}
+}
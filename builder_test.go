@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestImmutableBuilder(t *testing.T) {
+	b := NewImmutableBuilder(0)
+	res := b.Finish()
+	if mess, diff := diff(res.String(), "0 []"); diff {
+		t.Errorf("Finish: %s", mess)
+	}
+	Consistent("Finish empty", t, res)
+
+	add := func(b *ImmutableBuilder) {
+		b.AddCost(2, 0, 5)
+		b.AddEdge(0, 1)
+		b.AddCost(2, 0, 3)
+		b.AddBothCost(1, 2, 4)
+		b.AddCost(2, 0, 7)
+		b.AddBoth(3, 3)
+	}
+	for _, test := range []struct {
+		policy DuplicatePolicy
+		exp    string
+	}{
+		{KeepDuplicates, "4 [(0 1) {1 2}:4 (2 0):3 (2 0):5 (2 0):7 (3 3)]"},
+		{KeepFirst, "4 [(0 1) {1 2}:4 (2 0):5 (3 3)]"},
+		{MinCost, "4 [(0 1) {1 2}:4 (2 0):3 (3 3)]"},
+		{SumCosts, "4 [(0 1) {1 2}:4 (2 0):15 (3 3)]"},
+	} {
+		b := NewImmutableBuilder(4)
+		b.Duplicates = test.policy
+		add(b)
+		res := b.Finish()
+		if mess, diff := diff(res.String(), test.exp); diff {
+			t.Errorf("Finish %d: %s", test.policy, mess)
+		}
+		if test.policy == KeepDuplicates {
+			if mess, diff := diff(Check(res), Stats{5, 2, 5, 1, 0}); diff {
+				t.Errorf("Finish: %s", mess)
+			}
+		} else {
+			Consistent("Finish", t, res)
+		}
+	}
+
+	b = NewImmutableBuilder(3)
+	b.Duplicates = SumCosts
+	b.AddCost(0, 1, 2)
+	b.AddCost(0, 1, -2)
+	b.AddEdge(1, 2)
+	res = b.Finish()
+	if mess, diff := diff(res.String(), "3 [(0 1) (1 2)]"); diff {
+		t.Errorf("Finish: %s", mess)
+	}
+	if res.cost != nil {
+		t.Errorf("Finish: costs stored for unweighted graph")
+	}
+
+	n := 10
+	g := New(n)
+	b = NewImmutableBuilder(n)
+	b.Duplicates = KeepFirst
+	for i := 0; i < 4*n; i++ {
+		v, w, c := rand.Intn(n), rand.Intn(n), rand.Int63n(3)
+		if !g.Edge(v, w) {
+			g.AddCost(v, w, c)
+		}
+		b.AddCost(v, w, c)
+	}
+	if mess, diff := diff(b.Finish().String(), Sort(g).String()); diff {
+		t.Errorf("Finish rand: %s", mess)
+	}
+	if mess, diff := diff(b.Finish().String(), "10 []"); diff {
+		t.Errorf("Finish reuse: %s", mess)
+	}
+}
@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Canonical returns a canonical labeling of g: a permutation of its
+// vertices such that two graphs get the same relabeled graph canon
+// if and only if they are isomorphic. The vertex v in g becomes
+// perm[v] in canon. Edge costs and multiple edges are taken into
+// account, and Equal can be used to compare the canonical graphs;
+// the string returned by canon.String() is suitable as a hash key.
+//
+// The implementation uses an individualization-refinement search in the
+// style of McKay's nauty, with equitable partition refinement and pruning
+// by automorphisms. The time complexity is exponential in the worst case,
+// but typically much better.
+func Canonical(g Iterator) (perm []int, canon *Immutable) {
+	s := newCanonSearch(g)
+	s.run()
+	perm = s.bestPerm
+	inv := make([]int, len(perm))
+	for v, i := range perm {
+		inv[i] = v
+	}
+	return perm, Sort(&relabeled{g, perm, inv})
+}
+
+// Automorphisms returns a set of generators for the automorphism group of g.
+// An automorphism is a permutation p of the vertices that maps g to itself:
+// there are k edges of cost c from v to w iff there are k edges of cost c
+// from p[v] to p[w]. The identity permutation is not included, and an empty
+// slice is returned if g has no other automorphisms.
+//
+// The implementation uses an individualization-refinement search in the
+// style of McKay's nauty. The time complexity is exponential in the worst
+// case, but typically much better.
+func Automorphisms(g Iterator) (generators [][]int) {
+	s := newCanonSearch(g)
+	s.run()
+	return s.generators
+}
+
+// relabeled is g with vertex v renamed perm[v]; inv is the inverse of perm.
+type relabeled struct {
+	g         Iterator
+	perm, inv []int
+}
+
+func (r *relabeled) Order() int { return r.g.Order() }
+
+func (r *relabeled) Visit(v int, do func(w int, c int64) bool) bool {
+	return r.g.Visit(r.inv[v], func(w int, c int64) bool {
+		return do(r.perm[w], c)
+	})
+}
+
+// canonSearch holds the state of a canonical labeling search.
+type canonSearch struct {
+	out, in [][]neighbor // sorted edges from and to each vertex
+
+	// A labeling is represented by the certificate of the relabeled graph:
+	// a sorted list of (v, w, c) triples, one for each edge.
+	first, best         []int64
+	firstPerm, bestPerm []int
+	generators          [][]int
+}
+
+func newCanonSearch(g Iterator) *canonSearch {
+	h := Sort(g)
+	return &canonSearch{
+		out:        h.lists(),
+		in:         Transpose(h).lists(),
+		generators: [][]int{},
+	}
+}
+
+// run searches all leaves of the search tree, except those that can be
+// pruned by automorphisms.
+func (s *canonSearch) run() {
+	// A partition of the vertices into ordered cells is represented
+	// by a color for each vertex: the position of the first vertex
+	// of its cell in the ordering.
+	colors := make([]int, len(s.out))
+	s.search(colors, true)
+}
+
+// search explores the subtree rooted at a partition. It returns true
+// if it finds a leaf equivalent to the first leaf; in that case the rest
+// of the subtree is equivalent to an already explored part of the tree.
+func (s *canonSearch) search(colors []int, firstPath bool) (jump bool) {
+	colors = s.refine(colors)
+
+	// Find the first non-singleton cell.
+	n := len(colors)
+	size := make([]int, n)
+	for _, c := range colors {
+		size[c]++
+	}
+	target := -1
+	for c, k := range size {
+		if k > 1 {
+			target = c
+			break
+		}
+	}
+	if target == -1 {
+		return s.leaf(colors)
+	}
+
+	var cell []int
+	for v, c := range colors {
+		if c == target {
+			cell = append(cell, v)
+		}
+	}
+	var tried []int
+	for _, v := range cell {
+		if firstPath && len(tried) > 0 && s.sameOrbit(v, tried) {
+			continue
+		}
+		tried = append(tried, v)
+
+		// Individualize v: put it in a cell by itself in front of its old cell.
+		child := make([]int, n)
+		for w, c := range colors {
+			child[w] = c
+			if c == target && w != v {
+				child[w] = c + 1
+			}
+		}
+		if s.search(child, firstPath && len(tried) == 1) && !firstPath {
+			return true
+		}
+	}
+	return false
+}
+
+// leaf handles a discrete partition.
+func (s *canonSearch) leaf(perm []int) (jump bool) {
+	cert := s.certificate(perm)
+	switch {
+	case s.firstPerm == nil:
+		s.first, s.firstPerm = cert, perm
+		s.best, s.bestPerm = cert, perm
+		return false
+	case compareInt64s(cert, s.first) == 0:
+		s.automorphism(s.firstPerm, perm)
+		return true
+	}
+	switch compareInt64s(cert, s.best) {
+	case -1:
+		s.best, s.bestPerm = cert, perm
+	case 0:
+		s.automorphism(s.bestPerm, perm)
+	}
+	return false
+}
+
+// automorphism records the automorphism that maps v to q⁻¹(p(v)),
+// where p and q are two labelings that give the same graph.
+func (s *canonSearch) automorphism(p, q []int) {
+	n := len(p)
+	inv := make([]int, n)
+	for v, i := range q {
+		inv[i] = v
+	}
+	a := make([]int, n)
+	identity := true
+	for v := range a {
+		a[v] = inv[p[v]]
+		if a[v] != v {
+			identity = false
+		}
+	}
+	if !identity {
+		s.generators = append(s.generators, a)
+	}
+}
+
+// sameOrbit tells if v belongs to the same orbit as a vertex in list
+// under the group generated by the automorphisms found so far.
+func (s *canonSearch) sameOrbit(v int, list []int) bool {
+	orbits := makeSingletons(len(s.out))
+	for _, a := range s.generators {
+		for w, x := range a {
+			orbits.union(w, x)
+		}
+	}
+	for _, w := range list {
+		if orbits.find(v) == orbits.find(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// refine returns the coarsest equitable partition finer than colors.
+// Vertices stay in the same cell only if they have the same number
+// of edges of each cost to and from each cell. The new cells are ordered
+// in a way that only depends on the labeling of the old cells.
+func (s *canonSearch) refine(colors []int) []int {
+	n := len(colors)
+	order := make([]int, n)
+	for v := range order {
+		order[v] = v
+	}
+	sigs := make([][]int64, n)
+	for cells := -1; ; {
+		for v := range sigs {
+			sigs[v] = s.signature(v, colors, sigs[v][:0])
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return compareInt64s(sigs[order[i]], sigs[order[j]]) < 0
+		})
+		next := make([]int, n)
+		count := 0
+		for i, v := range order {
+			if i == 0 || compareInt64s(sigs[v], sigs[order[i-1]]) != 0 {
+				next[v] = i
+				count++
+			} else {
+				next[v] = next[order[i-1]]
+			}
+		}
+		colors = next
+		if count == cells {
+			return colors
+		}
+		cells = count
+	}
+}
+
+// signature returns the color of v, followed by the number of edges from v
+// and their sorted colors and costs, and then the same data for the edges
+// to v, appended to buf.
+func (s *canonSearch) signature(v int, colors []int, buf []int64) []int64 {
+	buf = append(buf, int64(colors[v]))
+	for _, edges := range [2][]neighbor{s.out[v], s.in[v]} {
+		buf = append(buf, int64(len(edges)))
+		start := len(buf)
+		for _, e := range edges {
+			buf = append(buf, int64(colors[e.vertex]), e.cost)
+		}
+		sort.Sort(int64Pairs(buf[start:]))
+	}
+	return buf
+}
+
+// certificate returns the sorted list of (perm[v], perm[w], c) triples
+// for all edges (v, w) of cost c.
+func (s *canonSearch) certificate(perm []int) []int64 {
+	var cert []int64
+	for v, edges := range s.out {
+		for _, e := range edges {
+			cert = append(cert, int64(perm[v]), int64(perm[e.vertex]), e.cost)
+		}
+	}
+	sort.Sort(int64Triples(cert))
+	return cert
+}
+
+// compareInt64s compares two lists lexicographically.
+func compareInt64s(a, b []int64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// int64Pairs sorts a list of pairs stored consecutively.
+type int64Pairs []int64
+
+func (p int64Pairs) Len() int { return len(p) / 2 }
+func (p int64Pairs) Less(i, j int) bool {
+	return compareInt64s(p[2*i:2*i+2], p[2*j:2*j+2]) < 0
+}
+func (p int64Pairs) Swap(i, j int) {
+	p[2*i], p[2*j] = p[2*j], p[2*i]
+	p[2*i+1], p[2*j+1] = p[2*j+1], p[2*i+1]
+}
+
+// int64Triples sorts a list of triples stored consecutively.
+type int64Triples []int64
+
+func (t int64Triples) Len() int { return len(t) / 3 }
+func (t int64Triples) Less(i, j int) bool {
+	return compareInt64s(t[3*i:3*i+3], t[3*j:3*j+3]) < 0
+}
+func (t int64Triples) Swap(i, j int) {
+	for k := 0; k < 3; k++ {
+		t[3*i+k], t[3*j+k] = t[3*j+k], t[3*i+k]
+	}
+}
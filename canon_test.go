@@ -0,0 +1,258 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	g := New(0)
+	perm, canon := Canonical(g)
+	if mess, diff := diff(perm, []int{}); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+	if mess, diff := diff(canon.String(), "0 []"); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+
+	// Paths with different labels.
+	g, h := New(3), New(3)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	h.AddBoth(2, 0)
+	h.AddBoth(0, 1)
+	_, c1 := Canonical(g)
+	_, c2 := Canonical(h)
+	if mess, diff := diff(c1.String(), c2.String()); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+	if mess, diff := diff(Equal(c1, c2), true); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+
+	// Costs and directions matter.
+	h = New(3)
+	h.AddBoth(0, 1)
+	h.AddBothCost(1, 2, 1)
+	_, c2 = Canonical(h)
+	if mess, diff := diff(Equal(c1, c2), false); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+	h = New(3)
+	h.AddBoth(0, 1)
+	h.Add(1, 2)
+	_, c2 = Canonical(h)
+	if mess, diff := diff(Equal(c1, c2), false); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+
+	// A 6-cycle and two triangles.
+	g, h = New(6), New(6)
+	for i := 0; i < 6; i++ {
+		g.AddBoth(i, (i+1)%6)
+	}
+	for i := 0; i < 3; i++ {
+		h.AddBoth(i, (i+1)%3)
+		h.AddBoth(i+3, (i+1)%3+3)
+	}
+	_, c1 = Canonical(g)
+	_, c2 = Canonical(h)
+	if mess, diff := diff(Equal(c1, c2), false); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+
+	// Random permutations.
+	for i := 0; i < 50; i++ {
+		n := 1 + rand.Intn(10)
+		g := New(n)
+		for j := 0; j < n+rand.Intn(2*n); j++ {
+			g.AddCost(rand.Intn(n), rand.Intn(n), int64(rand.Intn(2)))
+		}
+		h := New(n)
+		p := rand.Perm(n)
+		for v := 0; v < n; v++ {
+			g.Visit(v, func(w int, c int64) (skip bool) {
+				h.AddCost(p[v], p[w], c)
+				return
+			})
+		}
+		perm1, c1 := Canonical(g)
+		_, c2 := Canonical(h)
+		if mess, diff := diff(c1.String(), c2.String()); diff {
+			t.Errorf("Canonical %v %v: %s", g, h, mess)
+		}
+		// Check that canon is g relabeled by perm.
+		relabel := New(n)
+		for v := 0; v < n; v++ {
+			g.Visit(v, func(w int, c int64) (skip bool) {
+				relabel.AddCost(perm1[v], perm1[w], c)
+				return
+			})
+		}
+		if mess, diff := diff(Equal(c1, relabel), true); diff {
+			t.Errorf("Canonical %v: %s", g, mess)
+		}
+		// Canonical forms agree with isomorphism.
+		k := randomGraph(n, n)
+		_, c3 := Canonical(k)
+		_, iso := IsomorphicCost(g, k)
+		if mess, diff := diff(Equal(c1, c3), iso); diff {
+			t.Errorf("Canonical %v %v: %s", g, k, mess)
+		}
+	}
+
+	// Multigraphs.
+	m1 := Sort(&relabeled{g: multi{}, perm: []int{0, 1, 2}, inv: []int{0, 1, 2}})
+	m2 := Sort(&relabeled{g: multi{}, perm: []int{2, 0, 1}, inv: []int{1, 2, 0}})
+	_, c1 = Canonical(m1)
+	_, c2 = Canonical(m2)
+	if mess, diff := diff(c1.String(), c2.String()); diff {
+		t.Errorf("Canonical: %s", mess)
+	}
+}
+
+// multi is the multigraph 3 [2×(0 1) (1 2)].
+type multi struct{}
+
+func (multi) Order() int { return 3 }
+func (multi) Visit(v int, do func(w int, c int64) bool) bool {
+	switch v {
+	case 0:
+		return do(1, 0) || do(1, 0)
+	case 1:
+		return do(2, 0)
+	}
+	return false
+}
+
+func TestAutomorphisms(t *testing.T) {
+	g := New(3)
+	if mess, diff := diff(len(Automorphisms(g)), 2); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+	if mess, diff := diff(groupOrder(3, Automorphisms(g)), 6); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+
+	g.Add(0, 1)
+	g.Add(1, 2)
+	if mess, diff := diff(Automorphisms(g), [][]int{}); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+
+	// The Petersen graph has 120 automorphisms.
+	g = New(10)
+	for i := 0; i < 5; i++ {
+		g.AddBoth(i, (i+1)%5)
+		g.AddBoth(i, i+5)
+		g.AddBoth(i+5, (i+2)%5+5)
+	}
+	gens := Automorphisms(g)
+	checkAutomorphisms("Automorphisms", t, g, gens)
+	if mess, diff := diff(groupOrder(10, gens), 120); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+
+	// The cube has 48 automorphisms.
+	g = New(8)
+	for v := 0; v < 8; v++ {
+		for b := 1; b < 8; b <<= 1 {
+			g.Add(v, v^b)
+		}
+	}
+	gens = Automorphisms(g)
+	checkAutomorphisms("Automorphisms", t, g, gens)
+	if mess, diff := diff(groupOrder(8, gens), 48); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+
+	// A directed 7-cycle has 7 automorphisms.
+	g = New(7)
+	for v := 0; v < 7; v++ {
+		g.Add(v, (v+1)%7)
+	}
+	gens = Automorphisms(g)
+	checkAutomorphisms("Automorphisms", t, g, gens)
+	if mess, diff := diff(groupOrder(7, gens), 7); diff {
+		t.Errorf("Automorphisms: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(8, 6)
+		gens := Automorphisms(g)
+		checkAutomorphisms("Automorphisms", t, g, gens)
+		if mess, diff := diff(groupOrder(8, gens), bruteAutomorphisms(g)); diff {
+			t.Errorf("Automorphisms %v: %s", g, mess)
+		}
+	}
+}
+
+func checkAutomorphisms(mess string, t *testing.T, g *Mutable, gens [][]int) {
+	for _, a := range gens {
+		checkIsomorphism(mess, t, g, g, a, true)
+	}
+}
+
+// Compute the size of the group generated by gens.
+func groupOrder(n int, gens [][]int) int {
+	id := make([]int, n)
+	for i := range id {
+		id[i] = i
+	}
+	key := func(p []int) string { return fmt.Sprint(p) }
+	seen := map[string]bool{key(id): true}
+	for queue := [][]int{id}; len(queue) > 0; queue = queue[1:] {
+		p := queue[0]
+		for _, a := range gens {
+			q := make([]int, n)
+			for i := range q {
+				q[i] = a[p[i]]
+			}
+			if !seen[key(q)] {
+				seen[key(q)] = true
+				queue = append(queue, q)
+			}
+		}
+	}
+	return len(seen)
+}
+
+// Count all permutations that preserve edges.
+func bruteAutomorphisms(g *Mutable) (count int) {
+	n := g.Order()
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	var permute func(k int)
+	permute = func(k int) {
+		if k == n {
+			for v := 0; v < n; v++ {
+				for w := 0; w < n; w++ {
+					if g.Edge(v, w) != g.Edge(p[v], p[w]) {
+						return
+					}
+				}
+			}
+			count++
+			return
+		}
+		for i := k; i < n; i++ {
+			p[k], p[i] = p[i], p[k]
+			permute(k + 1)
+			p[k], p[i] = p[i], p[k]
+		}
+	}
+	permute(0)
+	return
+}
+
+func BenchmarkCanonical(b *testing.B) {
+	b.StopTimer()
+	g := randomGraph(30, 60)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Canonical(g)
+	}
+}
@@ -0,0 +1,224 @@
+package graph
+
+// Betweenness computes the betweenness centrality of each vertex in g:
+// the sum, over all ordered pairs of distinct vertices s and t,
+// of the fraction of shortest paths from s to t that pass through v.
+// Edge costs are disregarded; the length of a path is its number of edges.
+// In an undirected graph each path is counted twice, once in each direction.
+//
+// The implementation uses Brandes's algorithm.
+// The time complexity is O(|V|⋅|E|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func Betweenness(g Iterator) []float64 {
+	n := g.Order()
+	b := newBrandes(n)
+	for s := 0; s < n; s++ {
+		b.reset(s)
+		for queue := []int{s}; len(queue) > 0; {
+			v := queue[0]
+			queue = queue[1:]
+			b.order = append(b.order, v)
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				switch {
+				case b.dist[w] == -1:
+					b.dist[w] = b.dist[v] + 1
+					queue = append(queue, w)
+					fallthrough
+				case b.dist[w] == b.dist[v]+1:
+					b.sigma[w] += b.sigma[v]
+					b.pred[w] = append(b.pred[w], v)
+				}
+				return
+			})
+		}
+		b.accumulate(s)
+	}
+	return b.centrality
+}
+
+// BetweennessCost computes the betweenness centrality of each vertex in g:
+// the sum, over all ordered pairs of distinct vertices s and t,
+// of the fraction of shortest paths from s to t that pass through v.
+// The length of a path is the sum of its edge costs.
+// Only edges with positive cost are included.
+// In an undirected graph each path is counted twice, once in each direction.
+//
+// The implementation uses Brandes's algorithm.
+// The time complexity is O(|V|⋅(|E| + |V|)⋅log|V|), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func BetweennessCost(g Iterator) []float64 {
+	n := g.Order()
+	b := newBrandes(n)
+	for s := 0; s < n; s++ {
+		b.reset(s)
+		Q := emptyPrioQueue(b.dist)
+		Q.Push(s)
+		for Q.Len() > 0 {
+			v := Q.Pop()
+			b.order = append(b.order, v)
+			g.Visit(v, func(w int, d int64) (skip bool) {
+				if d <= 0 {
+					return
+				}
+				alt := b.dist[v] + d
+				switch {
+				case b.dist[w] == -1:
+					b.dist[w] = alt
+					Q.Push(w)
+				case alt < b.dist[w]:
+					b.dist[w] = alt
+					Q.Fix(w)
+				case alt > b.dist[w]:
+					return
+				default:
+					b.sigma[w] += b.sigma[v]
+					b.pred[w] = append(b.pred[w], v)
+					return
+				}
+				b.sigma[w] = b.sigma[v]
+				b.pred[w] = append(b.pred[w][:0], v)
+				return
+			})
+		}
+		b.accumulate(s)
+	}
+	return b.centrality
+}
+
+// brandes holds the state of Brandes's algorithm for a single source.
+type brandes struct {
+	dist       []int64   // distance from the source, or -1
+	sigma      []float64 // number of shortest paths from the source
+	pred       [][]int   // predecessors on shortest paths
+	order      []int     // vertices in order of non-decreasing distance
+	delta      []float64 // dependency of the source on each vertex
+	centrality []float64
+}
+
+func newBrandes(n int) *brandes {
+	return &brandes{
+		dist:       make([]int64, n),
+		sigma:      make([]float64, n),
+		pred:       make([][]int, n),
+		delta:      make([]float64, n),
+		centrality: make([]float64, n),
+	}
+}
+
+func (b *brandes) reset(s int) {
+	for v := range b.dist {
+		b.dist[v] = -1
+		b.sigma[v] = 0
+		b.pred[v] = b.pred[v][:0]
+		b.delta[v] = 0
+	}
+	b.dist[s], b.sigma[s] = 0, 1
+	b.order = b.order[:0]
+}
+
+// accumulate adds the dependencies of s, in order of non-increasing distance.
+func (b *brandes) accumulate(s int) {
+	for i := len(b.order) - 1; i >= 0; i-- {
+		w := b.order[i]
+		for _, v := range b.pred[w] {
+			b.delta[v] += b.sigma[v] / b.sigma[w] * (1 + b.delta[w])
+		}
+		if w != s {
+			b.centrality[w] += b.delta[w]
+		}
+	}
+}
+
+// Closeness computes the closeness centrality of each vertex v in g.
+// If r vertices, including v, can be reached from v, and their
+// total distance from v is d, the closeness of v is (r-1)/d
+// scaled by (r-1)/(n-1), where n is the number of vertices;
+// this is the Wasserman–Faust generalization to disconnected graphs.
+// A vertex that can't reach any other vertex has closeness 0.
+// Edge costs are disregarded; the length of a path is its number of edges.
+//
+// The time complexity is O(|V|⋅(|E| + |V|)), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func Closeness(g Iterator) []float64 {
+	return closeness(g, bfsDistances)
+}
+
+// ClosenessCost is like Closeness, but the length of a path
+// is the sum of its edge costs. Only edges with non-negative costs
+// are included.
+//
+// The time complexity is O(|V|⋅(|E| + |V|)⋅log|V|), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func ClosenessCost(g Iterator) []float64 {
+	return closeness(g, costDistances)
+}
+
+func closeness(g Iterator, distances func(Iterator, int) []int64) []float64 {
+	n := g.Order()
+	res := make([]float64, n)
+	for v := range res {
+		var r, sum int64
+		for _, d := range distances(g, v) {
+			if d > 0 {
+				r++
+				sum += d
+			}
+		}
+		if sum > 0 {
+			res[v] = float64(r) / float64(sum) * float64(r) / float64(n-1)
+		}
+	}
+	return res
+}
+
+// Harmonic computes the harmonic centrality of each vertex v in g:
+// the sum of 1/d(v, w) over all vertices w ≠ v reachable from v,
+// where d(v, w) is the length of a shortest path from v to w.
+// Edge costs are disregarded; the length of a path is its number of edges.
+//
+// The time complexity is O(|V|⋅(|E| + |V|)), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func Harmonic(g Iterator) []float64 {
+	return harmonic(g, bfsDistances)
+}
+
+// HarmonicCost is like Harmonic, but the length of a path is the sum of
+// its edge costs. Only edges with non-negative costs are included,
+// and vertices at distance 0 from v are disregarded.
+//
+// The time complexity is O(|V|⋅(|E| + |V|)⋅log|V|), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func HarmonicCost(g Iterator) []float64 {
+	return harmonic(g, costDistances)
+}
+
+func harmonic(g Iterator, distances func(Iterator, int) []int64) []float64 {
+	res := make([]float64, g.Order())
+	for v := range res {
+		for _, d := range distances(g, v) {
+			if d > 0 {
+				res[v] += 1 / float64(d)
+			}
+		}
+	}
+	return res
+}
+
+// bfsDistances returns the number of edges on a shortest path
+// from v to each vertex, or -1 if the vertex can't be reached.
+func bfsDistances(g Iterator, v int) []int64 {
+	dist := make([]int64, g.Order())
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[v] = 0
+	BFS(g, v, func(v, w int, _ int64) {
+		dist[w] = dist[v] + 1
+	})
+	return dist
+}
+
+func costDistances(g Iterator, v int) []int64 {
+	_, dist := ShortestPaths(g, v)
+	return dist
+}
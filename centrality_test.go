@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBetweenness(t *testing.T) {
+	// A path 0-1-2-3 with a shortcut 1-3.
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	g.AddBoth(2, 3)
+	g.AddBoth(1, 3)
+	checkFloats("Betweenness", t, Betweenness(g), []float64{0, 4, 0, 0})
+
+	// The shortcut is expensive: two shortest paths from 0 to 3.
+	g.AddBothCost(0, 1, 1)
+	g.AddBothCost(1, 2, 1)
+	g.AddBothCost(2, 3, 1)
+	g.AddBothCost(1, 3, 2)
+	checkFloats("BetweennessCost", t, BetweennessCost(g), []float64{0, 4, 2, 0})
+
+	checkFloats("Betweenness", t, Betweenness(New(0)), []float64{})
+	checkFloats("Betweenness", t, Betweenness(New(1)), []float64{0})
+
+	for i := 0; i < 50; i++ {
+		n := 1 + rand.Intn(10)
+		g := New(n)
+		for j := rand.Intn(3 * n); j > 0; j-- {
+			g.AddCost(rand.Intn(n), rand.Intn(n), int64(rand.Intn(4)))
+		}
+		unit := New(n)
+		for v := 0; v < n; v++ {
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				unit.AddCost(v, w, 1)
+				return
+			})
+		}
+		checkFloats("Betweenness", t, Betweenness(g), bruteBetweenness(unit))
+		checkFloats("BetweennessCost", t, BetweennessCost(g), bruteBetweenness(g))
+	}
+}
+
+func TestCloseness(t *testing.T) {
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	checkFloats("Closeness", t, Closeness(g), []float64{4.0 / 9, 2.0 / 3, 4.0 / 9, 0})
+	checkFloats("Harmonic", t, Harmonic(g), []float64{1.5, 2, 1.5, 0})
+
+	g.AddBothCost(0, 1, 3)
+	g.AddBothCost(1, 2, 1)
+	checkFloats("ClosenessCost", t, ClosenessCost(g), []float64{4.0 / 21, 1.0 / 3, 4.0 / 15, 0})
+	checkFloats("HarmonicCost", t, HarmonicCost(g), []float64{1.0/3 + 1.0/4, 1.0/3 + 1, 1 + 1.0/4, 0})
+
+	checkFloats("Closeness", t, Closeness(New(0)), []float64{})
+	checkFloats("Harmonic", t, Harmonic(New(1)), []float64{0})
+}
+
+// bruteBetweenness computes betweenness from the number of shortest paths
+// between each pair of vertices, using only edges with positive cost.
+func bruteBetweenness(g Iterator) []float64 {
+	n := g.Order()
+	dist := make([][]int64, n)
+	sigma := make([][]float64, n)
+	for s := 0; s < n; s++ {
+		sigma[s] = make([]float64, n)
+		dist[s] = make([]int64, n)
+		for t := range dist[s] {
+			dist[s][t] = -1
+		}
+		dist[s][s] = 0
+		// Bellman–Ford suffices for these tiny graphs.
+		for changed := true; changed; {
+			changed = false
+			for v := 0; v < n; v++ {
+				if dist[s][v] == -1 {
+					continue
+				}
+				g.Visit(v, func(w int, c int64) (skip bool) {
+					if c > 0 && (dist[s][w] == -1 || dist[s][v]+c < dist[s][w]) {
+						dist[s][w] = dist[s][v] + c
+						changed = true
+					}
+					return
+				})
+			}
+		}
+		order := make([]int, n)
+		for v := range order {
+			order[v] = v
+		}
+		sort.Slice(order, func(i, j int) bool { return dist[s][order[i]] < dist[s][order[j]] })
+		sigma[s][s] = 1
+		for _, v := range order {
+			if dist[s][v] == -1 {
+				continue
+			}
+			g.Visit(v, func(w int, c int64) (skip bool) {
+				if c > 0 && dist[s][v]+c == dist[s][w] {
+					sigma[s][w] += sigma[s][v]
+				}
+				return
+			})
+		}
+	}
+	res := make([]float64, n)
+	for s := 0; s < n; s++ {
+		for t := 0; t < n; t++ {
+			if s == t || dist[s][t] == -1 {
+				continue
+			}
+			for v := 0; v < n; v++ {
+				if v == s || v == t || dist[s][v] == -1 || dist[v][t] == -1 {
+					continue
+				}
+				if dist[s][v]+dist[v][t] == dist[s][t] {
+					res[v] += sigma[s][v] * sigma[v][t] / sigma[s][t]
+				}
+			}
+		}
+	}
+	return res
+}
+
+func checkFloats(mess string, t *testing.T, res, exp []float64) {
+	if len(res) != len(exp) {
+		t.Errorf("%s: %v; want %v", mess, res, exp)
+		return
+	}
+	for i := range res {
+		if math.Abs(res[i]-exp[i]) > 1e-9 {
+			t.Errorf("%s: %v; want %v", mess, res, exp)
+			return
+		}
+	}
+}
+
+func BenchmarkBetweenness(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := New(n)
+	for i := 0; i < 2*n; i++ {
+		g.AddBoth(rand.Intn(n), rand.Intn(n))
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Betweenness(g)
+	}
+}
@@ -0,0 +1,249 @@
+package graph
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// MaximalCliques calls the do function for each maximal clique in g.
+// A clique is a set of vertices that are all adjacent to each other;
+// it's maximal if no other vertex can be added to it.
+// The vertices of each clique are listed in increasing order,
+// and the do function may keep the slice.
+// If do returns true, MaximalCliques returns immediately,
+// skipping any remaining cliques, and returns true.
+//
+// Edges are treated as undirected and self-loops are disregarded.
+//
+// The implementation uses the Bron–Kerbosch algorithm with pivoting,
+// with the outer level of recursion in degeneracy order.
+// If g is an *Immutable with an edge (w, v) for each edge (v, w), and without
+// self-loops or multiple edges, its sorted neighbor lists are used directly;
+// candidate sets are intersected with them by merging or binary search.
+// The time complexity is O(d⋅|V|⋅3ᵈᐟ³), where d is the degeneracy of
+// the graph and |V| the number of vertices.
+func MaximalCliques(g Iterator, do func(clique []int) (skip bool)) (aborted bool) {
+	s := newCliqueSearch(g)
+	s.report = func(R []int) bool {
+		clique := append([]int{}, R...)
+		sort.Ints(clique)
+		return do(clique)
+	}
+	return s.run()
+}
+
+// MaxClique returns a maximum clique in g, a largest set of vertices
+// that are all adjacent to each other, listed in increasing order.
+//
+// Edges are treated as undirected and self-loops are disregarded.
+//
+// The implementation uses the Bron–Kerbosch algorithm with pivoting,
+// pruning all branches that can't produce a larger clique than the
+// best one found so far. The time complexity is exponential in the
+// worst case.
+func MaxClique(g Iterator) (clique []int) {
+	s := newCliqueSearch(g)
+	clique = []int{}
+	s.prune = func(r, p int) bool {
+		return r+p <= len(clique)
+	}
+	s.report = func(R []int) bool {
+		if len(R) > len(clique) {
+			clique = append(clique[:0], R...)
+		}
+		return false
+	}
+	s.run()
+	sort.Ints(clique)
+	return clique
+}
+
+// cliqueSearch holds the state of a Bron–Kerbosch search.
+type cliqueSearch struct {
+	g *Immutable // the underlying undirected simple graph
+
+	// prune tells if a branch with a clique of size r and
+	// p remaining candidates can be skipped; it may be nil.
+	prune func(r, p int) bool
+
+	// report is called for each maximal clique found.
+	report func(R []int) (skip bool)
+}
+
+func newCliqueSearch(g Iterator) *cliqueSearch {
+	return &cliqueSearch{g: undirectedSimple(g)}
+}
+
+// intersect returns the vertices in the sorted list P that are
+// adjacent to v, appended to res.
+func (s *cliqueSearch) intersect(res, P []int, v int) []int {
+	neighbors, _ := s.g.neighbors(v)
+	return intersectSorted(res, P, neighbors, v)
+}
+
+// run starts the search; each vertex v in degeneracy order is used
+// as the starting point for the cliques containing v but no vertex
+// preceding v in the order.
+func (s *cliqueSearch) run() (aborted bool) {
+	order := degeneracyOrder(s.g)
+	pos := make([]int, len(order))
+	for i, v := range order {
+		pos[v] = i
+	}
+	for _, v := range order {
+		var P, X []int
+		neighbors, _ := s.g.neighbors(v)
+		for _, x := range neighbors {
+			if w := int(x); pos[w] > pos[v] {
+				P = append(P, w)
+			} else {
+				X = append(X, w)
+			}
+		}
+		if s.search([]int{v}, P, X) {
+			return true
+		}
+	}
+	return
+}
+
+// search extends the clique R with vertices from P, excluding cliques
+// that contain a vertex from X. P and X are sorted.
+func (s *cliqueSearch) search(R, P, X []int) (aborted bool) {
+	if len(P) == 0 {
+		if len(X) == 0 {
+			return s.report(R)
+		}
+		return
+	}
+	if s.prune != nil && s.prune(len(R), len(P)) {
+		return
+	}
+
+	// Choose a pivot u from P ∪ X with as many neighbors in P as possible;
+	// only vertices not adjacent to u need to be tried.
+	u, most := -1, -1
+	var buf []int
+	for _, Y := range [2][]int{P, X} {
+		for _, w := range Y {
+			if buf = s.intersect(buf[:0], P, w); len(buf) > most {
+				u, most = w, len(buf)
+			}
+		}
+	}
+	var candidates []int
+	for _, v := range P {
+		if !s.g.Edge(u, v) {
+			candidates = append(candidates, v)
+		}
+	}
+
+	P = append([]int{}, P...)
+	X = append([]int{}, X...)
+	for _, v := range candidates {
+		if s.search(append(R, v), s.intersect(nil, P, v), s.intersect(nil, X, v)) {
+			return true
+		}
+		// Move v from P to X.
+		i := sort.SearchInts(P, v)
+		P = append(P[:i], P[i+1:]...)
+		j := sort.SearchInts(X, v)
+		X = append(X, 0)
+		copy(X[j+1:], X[j:])
+		X[j] = v
+	}
+	return
+}
+
+// intersectSorted appends the elements common to the sorted lists
+// a and b, except x, to res. If b is much longer than a, the elements
+// of a are looked up in b by binary search; otherwise the lists are merged.
+func intersectSorted(res, a []int, b []int32, x int) []int {
+	if len(a)*bits.Len(uint(len(b))) < len(b) {
+		for _, v := range a {
+			i := sort.Search(len(b), func(i int) bool { return v <= int(b[i]) })
+			if i < len(b) && int(b[i]) == v && v != x {
+				res = append(res, v)
+			}
+			b = b[i:]
+		}
+		return res
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < int(b[j]):
+			i++
+		case a[i] > int(b[j]):
+			j++
+		default:
+			if a[i] != x {
+				res = append(res, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// degeneracyOrder returns the vertices of the undirected simple graph g
+// in degeneracy order: each vertex v has at most core[v] neighbors
+// following it in the order, where core[v] is the core number of v.
+// The time complexity is O(|E| + |V|).
+func degeneracyOrder(g *Immutable) (order []int) {
+	order, _ = degeneracy(g)
+	return
+}
+
+// degeneracy computes a degeneracy order and the core number of each vertex
+// by repeatedly removing a vertex of minimum degree, using the bucket
+// algorithm of Batagelj and Zaveršnik.
+func degeneracy(g *Immutable) (order, core []int) {
+	n := g.Order()
+	degree := make([]int, n)
+	maxDegree := 0
+	for v := range degree {
+		degree[v] = g.Degree(v)
+		maxDegree = max(maxDegree, degree[v])
+	}
+
+	// Bucket sort the vertices by degree: vertices with degree d are
+	// stored in sorted[start[d]:start[d+1]], and pos[v] is the index of v.
+	start := make([]int, maxDegree+2)
+	for _, d := range degree {
+		start[d+1]++
+	}
+	for d := 1; d < len(start); d++ {
+		start[d] += start[d-1]
+	}
+	sorted := make([]int, n)
+	pos := make([]int, n)
+	next := append([]int{}, start...)
+	for v, d := range degree {
+		pos[v] = next[d]
+		sorted[pos[v]] = v
+		next[d]++
+	}
+
+	core = make([]int, n)
+	for i, v := range sorted {
+		core[v] = degree[v]
+		neighbors, _ := g.neighbors(v)
+		for _, x := range neighbors {
+			w := int(x)
+			if pos[w] <= i || degree[w] <= degree[v] {
+				continue
+			}
+			// Move w to the front of its bucket and decrement its degree.
+			d := degree[w]
+			u := sorted[start[d]]
+			if u != w {
+				sorted[pos[w]], sorted[start[d]] = u, w
+				pos[u], pos[w] = pos[w], start[d]
+			}
+			start[d]++
+			degree[w]--
+		}
+	}
+	return sorted, core
+}
@@ -0,0 +1,282 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestMaximalCliques(t *testing.T) {
+	g := New(0)
+	var res [][]int
+	collect := func(clique []int) (skip bool) {
+		res = append(res, clique)
+		return
+	}
+	MaximalCliques(g, collect)
+	if mess, diff := diff(len(res), 0); diff {
+		t.Errorf("MaximalCliques: %s", mess)
+	}
+
+	g = New(7)
+	g.AddBoth(0, 1) //  0 -- 1 -- 4    6
+	g.AddBoth(0, 2) //  | \/ |   |
+	g.AddBoth(0, 3) //  | /\ |   |
+	g.AddBoth(1, 2) //  2 -- 3   5
+	g.AddBoth(1, 3)
+	g.AddBoth(2, 3)
+	g.AddBoth(1, 4)
+	g.Add(5, 4)
+	g.Add(6, 6)
+	exp := [][]int{{0, 1, 2, 3}, {1, 4}, {4, 5}, {6}}
+	for _, h := range []Iterator{g, Sort(g)} {
+		res = nil
+		aborted := MaximalCliques(h, collect)
+		sortCliques(res)
+		if mess, diff := diff(res, exp); diff {
+			t.Errorf("MaximalCliques: %s", mess)
+		}
+		if mess, diff := diff(aborted, false); diff {
+			t.Errorf("MaximalCliques: %s", mess)
+		}
+	}
+
+	count := 0
+	aborted := MaximalCliques(g, func(clique []int) (skip bool) {
+		count++
+		return count == 2
+	})
+	if mess, diff := diff(count, 2); diff {
+		t.Errorf("MaximalCliques: %s", mess)
+	}
+	if mess, diff := diff(aborted, true); diff {
+		t.Errorf("MaximalCliques: %s", mess)
+	}
+
+	// Compare with brute force.
+	for i := 0; i < 20; i++ {
+		g := randomGraph(10, 25)
+		for _, h := range []Iterator{g, Sort(g)} {
+			res = nil
+			MaximalCliques(h, collect)
+			sortCliques(res)
+			if mess, diff := diff(res, bruteCliques(g)); diff {
+				t.Errorf("MaximalCliques %v: %s", g, mess)
+			}
+		}
+	}
+}
+
+func TestMaxClique(t *testing.T) {
+	g := New(0)
+	if mess, diff := diff(MaxClique(g), []int{}); diff {
+		t.Errorf("MaxClique: %s", mess)
+	}
+
+	g = New(1)
+	if mess, diff := diff(MaxClique(g), []int{0}); diff {
+		t.Errorf("MaxClique: %s", mess)
+	}
+
+	g = New(6)
+	g.AddBoth(0, 1)
+	g.AddBoth(2, 3)
+	g.AddBoth(3, 4)
+	g.AddBoth(1, 3)
+	g.Add(5, 1)
+	g.Add(3, 5)
+	if mess, diff := diff(MaxClique(g), []int{1, 3, 5}); diff {
+		t.Errorf("MaxClique: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(12, 40)
+		max := 0
+		for _, c := range bruteCliques(g) {
+			if len(c) > max {
+				max = len(c)
+			}
+		}
+		for _, h := range []Iterator{g, Sort(g)} {
+			clique := MaxClique(h)
+			if mess, diff := diff(len(clique), max); diff {
+				t.Errorf("MaxClique %v: %s", g, mess)
+			}
+			for _, v := range clique {
+				for _, w := range clique {
+					if v != w && !g.Edge(v, w) {
+						t.Errorf("MaxClique %v: %v is not a clique", g, clique)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestUndirectedSimple(t *testing.T) {
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	g.AddBoth(2, 0)
+	h := Sort(g)
+	if undirectedSimple(h) != h {
+		t.Errorf("undirectedSimple: %v copied", h)
+	}
+	g.Add(3, 2)
+	g.Add(3, 3)
+	for _, h := range []Iterator{g, Sort(g)} {
+		res := undirectedSimple(h)
+		if res == h {
+			t.Errorf("undirectedSimple: %v not copied", h)
+		}
+		if mess, diff := diff(res.String(), "4 [{0 1} {0 2} {1 2} {2 3}]"); diff {
+			t.Errorf("undirectedSimple: %s", mess)
+		}
+	}
+
+	for _, s := range []string{"0 []", "2 [{0 1}]", "3 [{0 1} (1 1) 2×{1 2}]"} {
+		h, _ := Parse(s)
+		if !symmetric(h) {
+			t.Errorf("symmetric(%v) = false", h)
+		}
+	}
+	for _, s := range []string{"2 [(0 1)]", "3 [{0 1} (2 1)]", "3 [{0 1} (1 2)]", "3 [(0 2) (1 0) (2 1)]"} {
+		h, _ := Parse(s)
+		if symmetric(h) {
+			t.Errorf("symmetric(%v) = true", h)
+		}
+	}
+}
+
+func TestIntersectSorted(t *testing.T) {
+	b := make([]int32, 100)
+	for i := range b {
+		b[i] = int32(2 * i)
+	}
+	long := make([]int, 30)
+	for i := range long {
+		long[i] = 3 * i
+	}
+	// Binary search is used for the short lists and merging for long.
+	for _, a := range [][]int{{}, {1, 4, 5, 6}, {0, 3, 198, 199, 200}, long} {
+		var exp []int
+		for _, v := range a {
+			if v%2 == 0 && v < 200 && v != 4 {
+				exp = append(exp, v)
+			}
+		}
+		if mess, diff := diff(intersectSorted(nil, a, b, 4), exp); diff {
+			t.Errorf("intersectSorted(%v): %s", a, mess)
+		}
+	}
+}
+
+func TestDegeneracy(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		g := randomGraph(15, 30)
+		adj := undirected(g)
+		order, core := degeneracy(undirectedSimple(g))
+		if mess, diff := diff(core, bruteCore(adj)); diff {
+			t.Errorf("degeneracy %v: %s", g, mess)
+		}
+		// Each vertex v must have at most core[v] later neighbors.
+		pos := make([]int, len(order))
+		for i, v := range order {
+			pos[v] = i
+		}
+		for v, neighbors := range adj {
+			later := 0
+			for _, w := range neighbors {
+				if pos[w] > pos[v] {
+					later++
+				}
+			}
+			if later > core[v] {
+				t.Errorf("degeneracy %v: %v", g, order)
+			}
+		}
+	}
+}
+
+// Compute the k-cores by repeatedly removing vertices of degree < k.
+func bruteCore(adj [][]int) []int {
+	n := len(adj)
+	core := make([]int, n)
+	for k := 1; k < n; k++ {
+		removed := make([]bool, n)
+		for again := true; again; {
+			again = false
+			for v := range adj {
+				deg := 0
+				for _, w := range adj[v] {
+					if !removed[w] {
+						deg++
+					}
+				}
+				if !removed[v] && deg < k {
+					removed[v], again = true, true
+				}
+			}
+		}
+		for v := range adj {
+			if !removed[v] {
+				core[v] = k
+			}
+		}
+	}
+	return core
+}
+
+func sortCliques(cliques [][]int) {
+	sort.Slice(cliques, func(i, j int) bool {
+		return fmt.Sprint(cliques[i]) < fmt.Sprint(cliques[j])
+	})
+}
+
+// Check all subsets of vertices.
+func bruteCliques(g *Mutable) (res [][]int) {
+	n := g.Order()
+	isClique := func(set int) bool {
+		for v := 0; v < n; v++ {
+			for w := 0; w < n; w++ {
+				if v != w && set&(1<<uint(v)) != 0 && set&(1<<uint(w)) != 0 &&
+					!g.Edge(v, w) && !g.Edge(w, v) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for set := 1; set < 1<<uint(n); set++ {
+		if !isClique(set) {
+			continue
+		}
+		maximal := true
+		for v := 0; v < n && maximal; v++ {
+			if set&(1<<uint(v)) == 0 && isClique(set|1<<uint(v)) {
+				maximal = false
+			}
+		}
+		if !maximal {
+			continue
+		}
+		var clique []int
+		for v := 0; v < n; v++ {
+			if set&(1<<uint(v)) != 0 {
+				clique = append(clique, v)
+			}
+		}
+		res = append(res, clique)
+	}
+	sortCliques(res)
+	return
+}
+
+func BenchmarkMaximalCliques(b *testing.B) {
+	b.StopTimer()
+	g := Sort(randomGraph(1000, 10000))
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		MaximalCliques(g, func([]int) bool { return false })
+	}
+}
@@ -0,0 +1,309 @@
+package graph
+
+import (
+	"sort"
+	"strconv"
+)
+
+// GreedyColoring returns a coloring of g's vertices such that no edge
+// connects two vertices of the same color. The vertices are colored
+// in the given order, each one receiving the smallest color not already
+// used by a neighbor. If order is nil, the vertices are colored in
+// increasing numerical order; otherwise order must be a permutation
+// of the vertices, or the function panics.
+//
+// The color of v is colors[v]; the colors are numbered from 0 and up.
+// Use ColorClasses to get the vertices of each color.
+// Edges are treated as undirected and self-loops are disregarded.
+//
+// The time complexity is O(|E|⋅log|V| + |V|), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func GreedyColoring(g Iterator, order []int) (colors []int) {
+	n := g.Order()
+	if order == nil {
+		order = make([]int, n)
+		for v := range order {
+			order[v] = v
+		}
+	}
+	if len(order) != n {
+		panic("order has " + strconv.Itoa(len(order)) + " vertices, want " + strconv.Itoa(n))
+	}
+	seen := make([]bool, n)
+	for _, v := range order {
+		if v < 0 || v >= n || seen[v] {
+			panic("order isn't a permutation: bad vertex " + strconv.Itoa(v))
+		}
+		seen[v] = true
+	}
+	adj := undirected(g)
+	colors = make([]int, n)
+	for v := range colors {
+		colors[v] = -1
+	}
+	// used[c] == v+1 if color c is used by a neighbor of v.
+	used := make([]int, n+1)
+	for _, v := range order {
+		for _, w := range adj[v] {
+			if c := colors[w]; c >= 0 {
+				used[c] = v + 1
+			}
+		}
+		c := 0
+		for used[c] == v+1 {
+			c++
+		}
+		colors[v] = c
+	}
+	return
+}
+
+// ColorClasses returns the partition of the vertices into color classes
+// given by colors, where colors[v] is the color of v, as returned by
+// GreedyColoring, DSatur and ChromaticNumber. The vertices with color c
+// are listed in increasing order in classes[c]. Each class is an
+// independent set; for a coloring with two colors, classes[0] is
+// a bipartition of the kind returned by Bipartition.
+func ColorClasses(colors []int) (classes [][]int) {
+	classes = [][]int{}
+	for v, c := range colors {
+		for len(classes) <= c {
+			classes = append(classes, []int{})
+		}
+		classes[c] = append(classes[c], v)
+	}
+	return
+}
+
+// DSatur returns a coloring of g's vertices such that no edge connects
+// two vertices of the same color. It uses Brélaz's DSatur heuristic:
+// the next vertex to be colored is the one with the largest number
+// of distinctly colored neighbors, with ties broken by degree.
+//
+// The color of v is colors[v]; the colors are numbered from 0 and up.
+// Edges are treated as undirected and self-loops are disregarded.
+//
+// The time complexity is O(|V|² + |E|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func DSatur(g Iterator) (colors []int) {
+	adj := undirected(g)
+	s := newSaturation(adj)
+	colors = make([]int, len(adj))
+	for range adj {
+		v := s.next()
+		c := 0
+		for s.count[v][c] > 0 {
+			c++
+		}
+		s.color(v, c)
+		colors[v] = c
+	}
+	return
+}
+
+// ChromaticNumber returns the smallest number of colors needed
+// to color g's vertices such that no edge connects two vertices
+// of the same color, together with such a coloring.
+//
+// The color of v is colors[v]; the colors are numbered from 0 to k-1.
+// Edges are treated as undirected and self-loops are disregarded.
+//
+// The implementation uses an exact DSatur-based backtracking search,
+// bounded from above by the DSatur heuristic and from below by the size
+// of a greedily constructed clique. The time complexity is exponential
+// and the function is intended for small graphs.
+func ChromaticNumber(g Iterator) (k int, colors []int) {
+	adj := undirected(g)
+	n := len(adj)
+	if n == 0 {
+		return 0, []int{}
+	}
+
+	best := DSatur(g)
+	k = 0
+	for _, c := range best {
+		if c+1 > k {
+			k = c + 1
+		}
+	}
+	lower := len(greedyClique(adj))
+	if k == lower {
+		return k, best
+	}
+
+	s := newSaturation(adj)
+	current := make([]int, n)
+	var search func(colored, used int)
+	search = func(colored, used int) {
+		if colored == n {
+			k = used
+			copy(best, current)
+			return
+		}
+		v := s.next()
+		for c := 0; c <= used && c < k-1 && k > lower; c++ {
+			if c < len(s.count[v]) && s.count[v][c] > 0 {
+				continue
+			}
+			s.color(v, c)
+			current[v] = c
+			search(colored+1, max(used, c+1))
+			s.uncolor(v, c)
+		}
+		s.reset(v)
+	}
+	search(0, 0)
+	return k, best
+}
+
+// saturation keeps track of colored neighbors for DSatur.
+type saturation struct {
+	adj     [][]int
+	colored []bool
+	count   [][]int // count[v][c] is the number of neighbors of v with color c
+	sat     []int   // sat[v] is the number of distinct colors among v's neighbors
+}
+
+func newSaturation(adj [][]int) *saturation {
+	n := len(adj)
+	s := &saturation{
+		adj:     adj,
+		colored: make([]bool, n),
+		count:   make([][]int, n),
+		sat:     make([]int, n),
+	}
+	for v := range s.count {
+		s.count[v] = make([]int, len(adj[v])+1)
+	}
+	return s
+}
+
+// next returns an uncolored vertex of maximum saturation,
+// with ties broken by degree and then by vertex number.
+func (s *saturation) next() int {
+	best := -1
+	for v, done := range s.colored {
+		switch {
+		case done:
+		case best == -1,
+			s.sat[v] > s.sat[best],
+			s.sat[v] == s.sat[best] && len(s.adj[v]) > len(s.adj[best]):
+			best = v
+		}
+	}
+	s.colored[best] = true
+	return best
+}
+
+// reset marks v as uncolored.
+func (s *saturation) reset(v int) {
+	s.colored[v] = false
+}
+
+func (s *saturation) color(v, c int) {
+	for _, w := range s.adj[v] {
+		if c >= len(s.count[w]) {
+			continue // A vertex with d neighbors never needs more than d+1 colors.
+		}
+		if s.count[w][c] == 0 {
+			s.sat[w]++
+		}
+		s.count[w][c]++
+	}
+}
+
+func (s *saturation) uncolor(v, c int) {
+	for _, w := range s.adj[v] {
+		if c >= len(s.count[w]) {
+			continue
+		}
+		s.count[w][c]--
+		if s.count[w][c] == 0 {
+			s.sat[w]--
+		}
+	}
+}
+
+// greedyClique returns a clique, built by repeatedly adding the vertex
+// of largest degree that is adjacent to all vertices in the clique.
+func greedyClique(adj [][]int) (clique []int) {
+	n := len(adj)
+	order := make([]int, n)
+	for v := range order {
+		order[v] = v
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(adj[order[i]]) > len(adj[order[j]])
+	})
+	count := make([]int, n) // number of clique members adjacent to v
+	for _, v := range order {
+		if count[v] != len(clique) {
+			continue
+		}
+		clique = append(clique, v)
+		for _, w := range adj[v] {
+			count[w]++
+		}
+	}
+	return
+}
+
+// undirected returns the sorted lists of neighbors of each vertex in
+// the undirected simple graph underlying g: there is an edge {v, w}
+// if v ≠ w and g contains an edge from v to w or from w to v.
+func undirected(g Iterator) [][]int {
+	n := g.Order()
+	adj := make([][]int, n)
+	for v := range adj {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			if v != w {
+				adj[v] = append(adj[v], w)
+				adj[w] = append(adj[w], v)
+			}
+			return
+		})
+	}
+	for v, neighbors := range adj {
+		sort.Ints(neighbors)
+		k := 0
+		for i, w := range neighbors {
+			if i == 0 || w != neighbors[i-1] {
+				neighbors[k] = w
+				k++
+			}
+		}
+		adj[v] = neighbors[:k]
+	}
+	return adj
+}
+
+// undirectedSimple returns the undirected simple graph underlying g,
+// as defined for undirected, with an edge in each direction for each
+// of its edges. If g is an *Immutable without self-loops or multiple edges
+// and with an edge (w, v) for each edge (v, w), g itself is returned.
+// Otherwise the graph is built by counting sort.
+// The time complexity is O(|E| + |V|).
+func undirectedSimple(g Iterator) *Immutable {
+	if h, ok := g.(*Immutable); ok && h.stats.Loops == 0 && h.stats.Multi == 0 && symmetric(h) {
+		return h
+	}
+	n := g.Order()
+	b := NewImmutableBuilder(n)
+	b.Duplicates = KeepFirst
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			if v != w {
+				b.AddBoth(v, w)
+			}
+			return
+		})
+	}
+	return b.Finish()
+}
+
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGreedyColoring(t *testing.T) {
+	g := New(0)
+	if mess, diff := diff(GreedyColoring(g, nil), []int{}); diff {
+		t.Errorf("GreedyColoring: %s", mess)
+	}
+
+	g = New(1)
+	g.Add(0, 0)
+	if mess, diff := diff(GreedyColoring(g, nil), []int{0}); diff {
+		t.Errorf("GreedyColoring: %s", mess)
+	}
+
+	// A crown graph is bipartite, but a bad order needs n/2 colors.
+	g = New(6)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if i != j {
+				g.AddBoth(2*i, 2*j+1)
+			}
+		}
+	}
+	if mess, diff := diff(GreedyColoring(g, nil), []int{0, 0, 1, 1, 2, 2}); diff {
+		t.Errorf("GreedyColoring: %s", mess)
+	}
+	order := []int{0, 2, 4, 1, 3, 5}
+	if mess, diff := diff(GreedyColoring(g, order), []int{0, 1, 0, 1, 0, 1}); diff {
+		t.Errorf("GreedyColoring: %s", mess)
+	}
+
+	// Edges are undirected.
+	g = New(3)
+	g.Add(0, 1)
+	g.Add(2, 1)
+	g.Add(2, 0)
+	if mess, diff := diff(GreedyColoring(g, nil), []int{0, 1, 2}); diff {
+		t.Errorf("GreedyColoring: %s", mess)
+	}
+
+	// The order must be a permutation.
+	for _, order := range [][]int{{0, 1}, {0, 1, 1}, {0, 1, 3}, {0, 1, 2, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GreedyColoring(g, %v): no panic", order)
+				}
+			}()
+			GreedyColoring(g, order)
+		}()
+	}
+}
+
+func TestColorClasses(t *testing.T) {
+	if mess, diff := diff(ColorClasses([]int{}), [][]int{}); diff {
+		t.Errorf("ColorClasses: %s", mess)
+	}
+	if mess, diff := diff(ColorClasses([]int{1, 0, 2, 0, 1}), [][]int{{1, 3}, {0, 4}, {2}}); diff {
+		t.Errorf("ColorClasses: %s", mess)
+	}
+
+	// A two-coloring gives a bipartition.
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	g.AddBoth(2, 3)
+	part, _ := Bipartition(g)
+	if mess, diff := diff(ColorClasses(GreedyColoring(g, nil))[0], part); diff {
+		t.Errorf("ColorClasses: %s", mess)
+	}
+}
+
+func TestDSatur(t *testing.T) {
+	g := New(0)
+	if mess, diff := diff(DSatur(g), []int{}); diff {
+		t.Errorf("DSatur: %s", mess)
+	}
+
+	// DSatur is exact for bipartite graphs.
+	g = New(6)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if i != j {
+				g.AddBoth(2*i, 2*j+1)
+			}
+		}
+	}
+	if mess, diff := diff(DSatur(g), []int{0, 1, 0, 1, 0, 1}); diff {
+		t.Errorf("DSatur: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(20, 40)
+		checkColoring("DSatur", t, g, DSatur(g))
+		checkColoring("GreedyColoring", t, g, GreedyColoring(g, nil))
+	}
+}
+
+func TestChromaticNumber(t *testing.T) {
+	g := New(0)
+	k, colors := ChromaticNumber(g)
+	if mess, diff := diff(k, 0); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+	if mess, diff := diff(colors, []int{}); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+
+	g = New(3)
+	k, colors = ChromaticNumber(g)
+	if mess, diff := diff(k, 1); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+	if mess, diff := diff(colors, []int{0, 0, 0}); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+
+	// The Petersen graph has chromatic number 3.
+	g = New(10)
+	for i := 0; i < 5; i++ {
+		g.AddBoth(i, (i+1)%5)
+		g.AddBoth(i, i+5)
+		g.AddBoth(i+5, (i+2)%5+5)
+	}
+	k, colors = ChromaticNumber(g)
+	if mess, diff := diff(k, 3); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+	checkColoring("ChromaticNumber", t, g, colors)
+
+	// The Grötzsch graph is triangle-free with chromatic number 4.
+	g = New(11)
+	for i := 0; i < 5; i++ {
+		g.AddBoth(i, (i+1)%5)
+		g.AddBoth(i+5, (i+1)%5)
+		g.AddBoth(i+5, (i+4)%5)
+		g.AddBoth(i+5, 10)
+	}
+	k, colors = ChromaticNumber(g)
+	if mess, diff := diff(k, 4); diff {
+		t.Errorf("ChromaticNumber: %s", mess)
+	}
+	checkColoring("ChromaticNumber", t, g, colors)
+
+	// Compare with brute force.
+	for i := 0; i < 20; i++ {
+		g := randomGraph(8, 14)
+		k, colors := ChromaticNumber(g)
+		checkColoring("ChromaticNumber", t, g, colors)
+		if mess, diff := diff(k, bruteChromatic(g)); diff {
+			t.Errorf("ChromaticNumber %v: %s", g, mess)
+		}
+	}
+}
+
+func randomGraph(n, m int) *Mutable {
+	g := New(n)
+	for i := 0; i < m; i++ {
+		g.AddBoth(rand.Intn(n), rand.Intn(n))
+	}
+	return g
+}
+
+// Check that no edge connects two vertices of the same color,
+// and that colors are numbered from 0.
+func checkColoring(mess string, t *testing.T, g Iterator, colors []int) {
+	for v := 0; v < g.Order(); v++ {
+		if colors[v] < 0 {
+			t.Errorf("%s: negative color %d for %d", mess, colors[v], v)
+		}
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			if v != w && colors[v] == colors[w] {
+				t.Errorf("%s: %v has same color for %d and %d", mess, colors, v, w)
+			}
+			return
+		})
+	}
+}
+
+// Try all colorings with k = 1, 2,... colors.
+func bruteChromatic(g *Mutable) int {
+	n := g.Order()
+	colors := make([]int, n)
+	var try func(v, k int) bool
+	try = func(v, k int) bool {
+		if v == n {
+			return true
+		}
+	next:
+		for c := 0; c < k; c++ {
+			for w := 0; w < v; w++ {
+				if colors[w] == c && g.Edge(v, w) {
+					continue next
+				}
+			}
+			colors[v] = c
+			if try(v+1, k) {
+				return true
+			}
+		}
+		return false
+	}
+	k := 0
+	for n > 0 && !try(0, k) {
+		k++
+	}
+	return k
+}
+
+func BenchmarkDSatur(b *testing.B) {
+	b.StopTimer()
+	g := randomGraph(1000, 5000)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = DSatur(g)
+	}
+}
@@ -0,0 +1,487 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Modularity returns the modularity of a partition of g's vertices into
+// communities: the fraction of the edge weight that falls within
+// communities, minus the expected fraction if the edges were distributed
+// at random with the same vertex degrees. Vertices that aren't included
+// in any of the sets are treated as communities of their own.
+//
+// Edge costs are used as weights, and edges with non-positive cost are
+// disregarded. If no edge has positive cost, as in an unweighted graph,
+// each edge has weight 1. Edges are treated as undirected: an edge from v to w of
+// cost c has weight c in both directions. For an undirected graph, where
+// each edge is represented in both directions, this is Newman's modularity.
+// The modularity of a graph without edges is 0.
+//
+// The time complexity is O(|E| + |V|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func Modularity(g Iterator, partition [][]int) float64 {
+	h := newCommGraph(g)
+	n := len(h.adj)
+	label := make([]int, n)
+	for v := range label {
+		label[v] = -1
+	}
+	for i, set := range partition {
+		for _, v := range set {
+			label[v] = i
+		}
+	}
+	for v := range label {
+		if label[v] == -1 {
+			label[v] = len(partition) + v
+		}
+	}
+	if h.total == 0 {
+		return 0
+	}
+	in := make([]int64, len(partition)+n)
+	tot := make([]int64, len(partition)+n)
+	for v, c := range label {
+		tot[c] += h.degree[v]
+		in[c] += h.loop[v]
+		for _, e := range h.adj[v] {
+			if label[e.vertex] == c {
+				in[c] += e.cost
+			}
+		}
+	}
+	W := float64(h.total)
+	q := 0.0
+	for c := range in {
+		a := float64(tot[c]) / W
+		q += float64(in[c])/W - a*a
+	}
+	return q
+}
+
+// Louvain returns a partition of g's vertices into communities
+// with high modularity. Each community is listed in increasing order,
+// and the communities are ordered by their smallest vertex.
+// Edge weights are defined as for Modularity.
+//
+// The implementation uses the Louvain method of Blondel et al.:
+// vertices are repeatedly moved to the neighboring community that gives
+// the largest increase in modularity, and then each community is collapsed
+// into a single vertex, until no further improvement is possible.
+// Each round of moves takes O(|E| + |V|) time, where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func Louvain(g Iterator) [][]int {
+	h := newCommGraph(g)
+	member := make([]int, len(h.adj))
+	for v := range member {
+		member[v] = v
+	}
+	for {
+		part := singletons(len(h.adj))
+		h.localMove(part)
+		k := relabel(part)
+		for v, x := range member {
+			member[v] = part[x]
+		}
+		if k == len(h.adj) {
+			break
+		}
+		h = h.aggregate(part, k)
+	}
+	return groups(member)
+}
+
+// Leiden returns a partition of g's vertices into communities
+// with high modularity. Each community is connected, listed
+// in increasing order, and the communities are ordered by their
+// smallest vertex. Edge weights are defined as for Modularity.
+//
+// The implementation uses the Leiden algorithm of Traag et al.,
+// which improves on the Louvain method by refining each community
+// into well-connected subcommunities before collapsing them.
+// The refinement step is deterministic: each vertex is merged with the
+// subcommunity that gives the largest increase in modularity.
+// Each round of moves takes O(|E| + |V|) time, where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func Leiden(g Iterator) [][]int {
+	h := newCommGraph(g)
+	member := make([]int, len(h.adj))
+	for v := range member {
+		member[v] = v
+	}
+	part := singletons(len(h.adj))
+	for {
+		h.localMove(part)
+		k := relabel(part)
+		if k == len(h.adj) {
+			break
+		}
+		refined := h.refine(part)
+		r := relabel(refined)
+		if r == len(h.adj) {
+			// The refinement didn't merge anything;
+			// collapse the connected parts of the communities instead.
+			refined = h.split(part)
+			if r = relabel(refined); r == len(h.adj) {
+				part = refined
+				break
+			}
+		}
+		next := make([]int, r)
+		for v, x := range refined {
+			next[x] = part[v]
+		}
+		for v, x := range member {
+			member[v] = refined[x]
+		}
+		h, part = h.aggregate(refined, r), next
+	}
+	for v, x := range member {
+		member[v] = part[x]
+	}
+	return groups(member)
+}
+
+// LabelPropagation returns a partition of g's vertices into communities
+// found by asynchronous label propagation. Each community is listed
+// in increasing order, and the communities are ordered by their
+// smallest vertex. Edge weights are defined as for Modularity.
+//
+// Initially, each vertex has a label of its own. The vertices are then
+// visited in random order, and each vertex adopts the label with the
+// largest total weight among its neighbors, breaking ties at random,
+// until every vertex has such a label. The seed determines the random
+// choices, and hence the result.
+// Each round takes O(|E| + |V|) time, where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func LabelPropagation(g Iterator, seed int64) [][]int {
+	h := newCommGraph(g)
+	n := len(h.adj)
+	rnd := rand.New(rand.NewSource(seed))
+	label := singletons(n)
+	order := singletons(n)
+	weight := make([]int64, n)
+	var touched, best []int
+	for i := 0; i < maxIterations; i++ {
+		rnd.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		changed := false
+		for _, v := range order {
+			touched = touched[:0]
+			for _, e := range h.adj[v] {
+				x := label[e.vertex]
+				if weight[x] == 0 {
+					touched = append(touched, x)
+				}
+				weight[x] += e.cost
+			}
+			var most int64
+			best = best[:0]
+			for _, x := range touched {
+				switch {
+				case weight[x] > most:
+					most = weight[x]
+					best = append(best[:0], x)
+				case weight[x] == most:
+					best = append(best, x)
+				}
+			}
+			if len(best) > 0 && weight[label[v]] != most {
+				label[v] = best[rnd.Intn(len(best))]
+				changed = true
+			}
+			for _, x := range touched {
+				weight[x] = 0
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return groups(label)
+}
+
+// commGraph is an undirected weighted graph used for community detection.
+type commGraph struct {
+	adj    [][]neighbor // sorted neighbors, excluding v itself, and weights
+	loop   []int64      // weight of the self-loops at v, counted twice
+	degree []int64      // total weight of the edges at v
+	total  int64        // total weight of all edges, counted twice
+}
+
+// newCommGraph returns the undirected graph with weights A + Aᵀ,
+// where A[v][w] is the total positive cost of the edges from v to w in g,
+// or the number of such edges if no edge in g has positive cost.
+func newCommGraph(g Iterator) *commGraph {
+	n := g.Order()
+	h := &commGraph{
+		adj:    make([][]neighbor, n),
+		loop:   make([]int64, n),
+		degree: make([]int64, n),
+	}
+	unit := true
+	for v := 0; v < n && unit; v++ {
+		g.Visit(v, func(_ int, c int64) (skip bool) {
+			unit = c <= 0
+			return !unit
+		})
+	}
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			if unit {
+				c = 1
+			}
+			switch {
+			case c <= 0:
+			case v == w:
+				h.loop[v] += 2 * c
+			default:
+				h.adj[v] = append(h.adj[v], neighbor{w, c})
+				h.adj[w] = append(h.adj[w], neighbor{v, c})
+			}
+			return
+		})
+	}
+	for v, edges := range h.adj {
+		sort.Slice(edges, func(i, j int) bool { return edges[i].vertex < edges[j].vertex })
+		// Merge parallel edges.
+		merged := edges[:0]
+		for i, e := range edges {
+			if i > 0 && e.vertex == merged[len(merged)-1].vertex {
+				merged[len(merged)-1].cost += e.cost
+				continue
+			}
+			merged = append(merged, e)
+		}
+		h.adj[v] = merged
+		h.degree[v] = h.loop[v]
+		for _, e := range merged {
+			h.degree[v] += e.cost
+		}
+		h.total += h.degree[v]
+	}
+	return h
+}
+
+// localMove repeatedly moves each vertex to the neighboring community
+// in part that gives the largest increase in modularity, until no
+// vertex can be moved. The communities are numbered from 0 to n-1.
+func (h *commGraph) localMove(part []int) {
+	n := len(h.adj)
+	tot := make([]int64, n)
+	for v, c := range part {
+		tot[c] += h.degree[v]
+	}
+	W := float64(h.total)
+	weight := make([]int64, n)
+	var touched []int
+	for moved := true; moved; {
+		moved = false
+		for v, c := range part {
+			touched = touched[:0]
+			for _, e := range h.adj[v] {
+				x := part[e.vertex]
+				if weight[x] == 0 {
+					touched = append(touched, x)
+				}
+				weight[x] += e.cost
+			}
+			// The gain of adding v to a community x, up to a constant factor,
+			// is weight[x] - tot[x]⋅k/W, where k is the degree of v.
+			k := float64(h.degree[v])
+			tot[c] -= h.degree[v]
+			best := c
+			bestGain := float64(weight[c]) - float64(tot[c])*k/W
+			for _, x := range touched {
+				if gain := float64(weight[x]) - float64(tot[x])*k/W; gain > bestGain+epsilon*k {
+					best, bestGain = x, gain
+				}
+			}
+			tot[best] += h.degree[v]
+			if best != c {
+				part[v] = best
+				moved = true
+			}
+			for _, x := range touched {
+				weight[x] = 0
+			}
+		}
+	}
+}
+
+// epsilon is the smallest relative modularity gain that counts as
+// an improvement; it guards against rounding errors.
+const epsilon = 1e-9
+
+// refine splits each community in part into well-connected subcommunities
+// and returns the refined partition, numbered from 0 to n-1.
+// Each vertex starts in a subcommunity of its own. A well-connected vertex,
+// still on its own, is merged with the well-connected subcommunity in the
+// same community that gives the largest non-negative increase in modularity.
+func (h *commGraph) refine(part []int) []int {
+	n := len(h.adj)
+	W := float64(h.total)
+	refined := singletons(n)
+	size := make([]int, n)
+	tot := make([]int64, n)  // total degree of each subcommunity
+	ext := make([]int64, n)  // weight from each subcommunity to the rest of its community
+	comm := make([]int64, n) // total degree of each community
+	for v, c := range part {
+		size[v] = 1
+		tot[v] = h.degree[v]
+		comm[c] += h.degree[v]
+		for _, e := range h.adj[v] {
+			if part[e.vertex] == c {
+				ext[v] += e.cost
+			}
+		}
+	}
+	wellConnected := func(r, c int) bool {
+		return float64(ext[r]) >= float64(tot[r])*float64(comm[c]-tot[r])/W
+	}
+	weight := make([]int64, n)
+	var touched []int
+	for v, c := range part {
+		if size[refined[v]] > 1 || !wellConnected(v, c) {
+			continue
+		}
+		touched = touched[:0]
+		for _, e := range h.adj[v] {
+			if part[e.vertex] != c {
+				continue
+			}
+			x := refined[e.vertex]
+			if weight[x] == 0 {
+				touched = append(touched, x)
+			}
+			weight[x] += e.cost
+		}
+		k := float64(h.degree[v])
+		best, bestGain := v, 0.0
+		for _, x := range touched {
+			if x == v || !wellConnected(x, c) {
+				continue
+			}
+			if gain := float64(weight[x]) - float64(tot[x])*k/W; gain >= bestGain && (best == v || gain > bestGain) {
+				best, bestGain = x, gain
+			}
+		}
+		if best != v {
+			refined[v] = best
+			size[best]++
+			size[v]--
+			tot[best] += tot[v]
+			ext[best] += ext[v] - 2*weight[best]
+		}
+		for _, x := range touched {
+			weight[x] = 0
+		}
+	}
+	return refined
+}
+
+// split returns the partition of each community in part into its
+// connected components.
+func (h *commGraph) split(part []int) []int {
+	n := len(h.adj)
+	res := make([]int, n)
+	for v := range res {
+		res[v] = -1
+	}
+	for v := range res {
+		if res[v] != -1 {
+			continue
+		}
+		res[v] = v
+		for queue := []int{v}; len(queue) > 0; {
+			u := queue[0]
+			queue = queue[1:]
+			for _, e := range h.adj[u] {
+				if w := e.vertex; res[w] == -1 && part[w] == part[v] {
+					res[w] = v
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// aggregate returns the graph where each of the k communities in part
+// is collapsed into a single vertex.
+func (h *commGraph) aggregate(part []int, k int) *commGraph {
+	a := &commGraph{
+		adj:    make([][]neighbor, k),
+		loop:   make([]int64, k),
+		degree: make([]int64, k),
+		total:  h.total,
+	}
+	members := make([][]int, k)
+	for v, c := range part {
+		members[c] = append(members[c], v)
+	}
+	weight := make([]int64, k)
+	var touched []int
+	for c, vs := range members {
+		touched = touched[:0]
+		for _, v := range vs {
+			a.loop[c] += h.loop[v]
+			a.degree[c] += h.degree[v]
+			for _, e := range h.adj[v] {
+				x := part[e.vertex]
+				if x == c {
+					a.loop[c] += e.cost
+					continue
+				}
+				if weight[x] == 0 {
+					touched = append(touched, x)
+				}
+				weight[x] += e.cost
+			}
+		}
+		sort.Ints(touched)
+		edges := make([]neighbor, len(touched))
+		for i, x := range touched {
+			edges[i] = neighbor{x, weight[x]}
+			weight[x] = 0
+		}
+		a.adj[c] = edges
+	}
+	return a
+}
+
+// singletons returns the list 0, 1, ..., n-1.
+func singletons(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// relabel renumbers the labels in part from 0 to k-1 in order of first
+// appearance, and returns the number of distinct labels k.
+func relabel(part []int) (k int) {
+	index := make(map[int]int)
+	for v, x := range part {
+		i, ok := index[x]
+		if !ok {
+			i = len(index)
+			index[x] = i
+		}
+		part[v] = i
+	}
+	return len(index)
+}
+
+// groups returns the vertices with the same label as a partition,
+// ordered by smallest vertex.
+func groups(label []int) [][]int {
+	part := append([]int{}, label...)
+	k := relabel(part)
+	res := make([][]int, k)
+	for v, x := range part {
+		res[x] = append(res[x], v)
+	}
+	return res
+}
@@ -0,0 +1,27 @@
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yourbasic/graph"
+	"github.com/yourbasic/graph/build"
+)
+
+func TestCommunitiesUnweighted(t *testing.T) {
+	// Two cliques joined by a bridge, without edge costs.
+	g := build.Kn(5).Join(build.Kn(5), build.Edge(0, 5))
+	exp := "[[0 1 2 3 4] [5 6 7 8 9]]"
+	for _, res := range [][][]int{
+		graph.Louvain(g),
+		graph.Leiden(g),
+		graph.LabelPropagation(g, 1),
+	} {
+		if s := fmt.Sprint(res); s != exp {
+			t.Errorf("communities: %s; want %s", s, exp)
+		}
+	}
+	if q := graph.Modularity(g, graph.Louvain(g)); q <= 0.4 {
+		t.Errorf("Modularity: %v; want > 0.4", q)
+	}
+}
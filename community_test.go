@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ringOfCliques returns k cliques of size s, each connected
+// to the next by a single edge. All edges have cost 1.
+func ringOfCliques(k, s int) *Mutable {
+	g := New(k * s)
+	for c := 0; c < k; c++ {
+		for i := 0; i < s; i++ {
+			for j := i + 1; j < s; j++ {
+				g.AddBothCost(c*s+i, c*s+j, 1)
+			}
+		}
+		g.AddBothCost(c*s, ((c+1)%k)*s+1, 1)
+	}
+	return g
+}
+
+func cliqueParts(k, s int) [][]int {
+	res := make([][]int, k)
+	for c := range res {
+		for i := 0; i < s; i++ {
+			res[c] = append(res[c], c*s+i)
+		}
+	}
+	return res
+}
+
+func TestModularity(t *testing.T) {
+	// Two triangles joined by an edge.
+	g := New(6)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}, {2, 3}} {
+		g.AddBothCost(e[0], e[1], 1)
+	}
+	q := Modularity(g, [][]int{{0, 1, 2}, {3, 4, 5}})
+	if math.Abs(q-(6.0/7-0.5)) > 1e-12 {
+		t.Errorf("Modularity: %v; want %v", q, 6.0/7-0.5)
+	}
+	if q := Modularity(g, [][]int{{0, 1, 2, 3, 4, 5}}); math.Abs(q) > 1e-12 {
+		t.Errorf("Modularity: %v; want 0", q)
+	}
+	// Vertices not listed are singletons.
+	if mess, diff := diff(Modularity(g, [][]int{}), Modularity(g, [][]int{{0}, {1}, {2}, {3}, {4}, {5}})); diff {
+		t.Errorf("Modularity: %s", mess)
+	}
+	if mess, diff := diff(Modularity(New(3), [][]int{{0, 1, 2}}), 0.0); diff {
+		t.Errorf("Modularity: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		n := 1 + rand.Intn(12)
+		g := New(n)
+		for j := rand.Intn(3 * n); j > 0; j-- {
+			g.AddCost(rand.Intn(n), rand.Intn(n), int64(rand.Intn(4)))
+		}
+		label := make([]int, n)
+		for v := range label {
+			label[v] = rand.Intn(3)
+		}
+		q, exp := Modularity(g, groups(label)), bruteModularity(g, label)
+		if math.Abs(q-exp) > 1e-9 {
+			t.Errorf("Modularity: %v; want %v", q, exp)
+		}
+	}
+}
+
+// bruteModularity computes the modularity of the undirected graph A + Aᵀ.
+func bruteModularity(g Iterator, label []int) float64 {
+	n := g.Order()
+	A := make([][]float64, n)
+	for v := range A {
+		A[v] = make([]float64, n)
+	}
+	unit := true
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(_ int, c int64) (skip bool) {
+			unit = unit && c <= 0
+			return
+		})
+	}
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			if unit {
+				c = 1
+			}
+			if c > 0 {
+				A[v][w] += float64(c)
+				A[w][v] += float64(c)
+			}
+			return
+		})
+	}
+	k := make([]float64, n)
+	W := 0.0
+	for v := range A {
+		for w := range A {
+			k[v] += A[v][w]
+		}
+		W += k[v]
+	}
+	if W == 0 {
+		return 0
+	}
+	q := 0.0
+	for v := range A {
+		for w := range A {
+			if label[v] == label[w] {
+				q += A[v][w] - k[v]*k[w]/W
+			}
+		}
+	}
+	return q / W
+}
+
+func TestLouvain(t *testing.T) {
+	if mess, diff := diff(Louvain(New(0)), [][]int{}); diff {
+		t.Errorf("Louvain: %s", mess)
+	}
+	if mess, diff := diff(Louvain(New(2)), [][]int{{0}, {1}}); diff {
+		t.Errorf("Louvain: %s", mess)
+	}
+	g := ringOfCliques(6, 5)
+	if mess, diff := diff(Louvain(g), cliqueParts(6, 5)); diff {
+		t.Errorf("Louvain ring of cliques: %s", mess)
+	}
+	for i := 0; i < 20; i++ {
+		g := randomCostGraph(1+rand.Intn(30), 60)
+		res := Louvain(g)
+		checkPartition("Louvain", t, g, res, false)
+		if q, q0 := Modularity(g, res), Modularity(g, [][]int{}); q < q0-1e-9 {
+			t.Errorf("Louvain: modularity %v < %v", q, q0)
+		}
+	}
+}
+
+func TestLeiden(t *testing.T) {
+	if mess, diff := diff(Leiden(New(0)), [][]int{}); diff {
+		t.Errorf("Leiden: %s", mess)
+	}
+	if mess, diff := diff(Leiden(New(2)), [][]int{{0}, {1}}); diff {
+		t.Errorf("Leiden: %s", mess)
+	}
+	g := ringOfCliques(6, 5)
+	if mess, diff := diff(Leiden(g), cliqueParts(6, 5)); diff {
+		t.Errorf("Leiden ring of cliques: %s", mess)
+	}
+	for i := 0; i < 20; i++ {
+		g := randomCostGraph(1+rand.Intn(30), 60)
+		res := Leiden(g)
+		checkPartition("Leiden", t, g, res, true)
+		if q, q0 := Modularity(g, res), Modularity(g, [][]int{}); q < q0-1e-9 {
+			t.Errorf("Leiden: modularity %v < %v", q, q0)
+		}
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	if mess, diff := diff(LabelPropagation(New(0), 1), [][]int{}); diff {
+		t.Errorf("LabelPropagation: %s", mess)
+	}
+	// Edges without positive cost are disregarded.
+	g := New(3)
+	g.AddBothCost(0, 1, 1)
+	g.AddBoth(1, 2)
+	if mess, diff := diff(LabelPropagation(g, 1), [][]int{{0, 1}, {2}}); diff {
+		t.Errorf("LabelPropagation: %s", mess)
+	}
+	g = ringOfCliques(6, 5)
+	for seed := int64(0); seed < 5; seed++ {
+		if mess, diff := diff(LabelPropagation(g, seed), cliqueParts(6, 5)); diff {
+			t.Errorf("LabelPropagation ring of cliques: %s", mess)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		g := randomCostGraph(1+rand.Intn(30), 60)
+		checkPartition("LabelPropagation", t, g, LabelPropagation(g, int64(i)), false)
+	}
+}
+
+func randomCostGraph(n, m int) *Mutable {
+	g := New(n)
+	for i := 0; i < m; i++ {
+		g.AddBothCost(rand.Intn(n), rand.Intn(n), int64(1+rand.Intn(5)))
+	}
+	return g
+}
+
+// Check that res is a partition in canonical order and,
+// if connected is set, that each community is connected.
+func checkPartition(mess string, t *testing.T, g Iterator, res [][]int, connected bool) {
+	n := g.Order()
+	label := make([]int, n)
+	for v := range label {
+		label[v] = -1
+	}
+	for i, set := range res {
+		for j, v := range set {
+			if label[v] != -1 {
+				t.Errorf("%s: %v is not a partition", mess, res)
+				return
+			}
+			label[v] = i
+			if j > 0 && set[j-1] >= v {
+				t.Errorf("%s: %v is not sorted", mess, res)
+			}
+		}
+		if i > 0 && res[i-1][0] >= set[0] {
+			t.Errorf("%s: %v is not sorted", mess, res)
+		}
+	}
+	for v := range label {
+		if label[v] == -1 {
+			t.Errorf("%s: %v is not a partition", mess, res)
+			return
+		}
+	}
+	if !connected {
+		return
+	}
+	for i, set := range res {
+		// Search the community, treating edges as undirected.
+		adj := undirected(g)
+		seen := map[int]bool{set[0]: true}
+		for queue := []int{set[0]}; len(queue) > 0; queue = queue[1:] {
+			for _, w := range adj[queue[0]] {
+				if label[w] == i && !seen[w] {
+					seen[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+		if len(seen) != len(set) {
+			t.Errorf("%s: %v is not connected", mess, set)
+		}
+	}
+}
+
+func BenchmarkLouvain(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := randomCostGraph(n, 5*n)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Louvain(g)
+	}
+}
+
+func BenchmarkLeiden(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := randomCostGraph(n, 5*n)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Leiden(g)
+	}
+}
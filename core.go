@@ -0,0 +1,52 @@
+package graph
+
+// CoreNumbers returns the core number of each vertex in g.
+// The k-core of a graph is its largest subgraph in which every vertex
+// has degree at least k, and the core number of v is the largest k
+// such that v belongs to the k-core.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The implementation uses the bucket algorithm of Batagelj and Zaveršnik.
+// If g is an *Immutable with an edge (w, v) for each edge (v, w), and without
+// self-loops or multiple edges, its sorted neighbor lists are used directly;
+// otherwise the underlying undirected simple graph is first built by
+// counting sort. The time complexity is O(|E| + |V|), where |E| is the
+// number of edges and |V| the number of vertices in the graph.
+func CoreNumbers(g Iterator) (core []int) {
+	_, core = degeneracy(undirectedSimple(g))
+	return
+}
+
+// KCore returns the vertices of the k-core of g, its largest subgraph
+// in which every vertex has degree at least k, in increasing order.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The time complexity is the same as for CoreNumbers.
+func KCore(g Iterator, k int) (vertices []int) {
+	vertices = []int{}
+	for v, c := range CoreNumbers(g) {
+		if c >= k {
+			vertices = append(vertices, v)
+		}
+	}
+	return
+}
+
+// Degeneracy returns a degeneracy ordering of g and its degeneracy k:
+// the smallest number such that every subgraph has a vertex of degree
+// at most k. Each vertex has at most k neighbors that follow it in
+// the ordering, which is produced by repeatedly removing a vertex of
+// minimum degree.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The time complexity is the same as for CoreNumbers.
+func Degeneracy(g Iterator) (order []int, k int) {
+	order, core := degeneracy(undirectedSimple(g))
+	for _, c := range core {
+		k = max(k, c)
+	}
+	return
+}
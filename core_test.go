@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestCoreNumbers(t *testing.T) {
+	// A 4-clique with a path 3-4-5 attached, and an isolated vertex.
+	g := New(7)
+	for v := 0; v < 4; v++ {
+		for w := v + 1; w < 4; w++ {
+			g.AddBoth(v, w)
+		}
+	}
+	g.AddBoth(3, 4)
+	g.AddBoth(4, 5)
+	g.Add(6, 6)
+	for _, h := range []Iterator{g, Sort(g)} {
+		if mess, diff := diff(CoreNumbers(h), []int{3, 3, 3, 3, 1, 1, 0}); diff {
+			t.Errorf("CoreNumbers: %s", mess)
+		}
+		if mess, diff := diff(KCore(h, 2), []int{0, 1, 2, 3}); diff {
+			t.Errorf("KCore: %s", mess)
+		}
+		if mess, diff := diff(KCore(h, 4), []int{}); diff {
+			t.Errorf("KCore: %s", mess)
+		}
+		order, k := Degeneracy(h)
+		if mess, diff := diff(k, 3); diff {
+			t.Errorf("Degeneracy: %s", mess)
+		}
+		if mess, diff := diff(len(order), 7); diff {
+			t.Errorf("Degeneracy: %s", mess)
+		}
+	}
+
+	order, k := Degeneracy(New(0))
+	if mess, diff := diff(order, []int{}); diff {
+		t.Errorf("Degeneracy: %s", mess)
+	}
+	if mess, diff := diff(k, 0); diff {
+		t.Errorf("Degeneracy: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		g := randomGraph(15, 30)
+		if mess, diff := diff(CoreNumbers(g), bruteCore(undirected(g))); diff {
+			t.Errorf("CoreNumbers %v: %s", g, mess)
+		}
+	}
+}
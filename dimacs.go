@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// ReadDIMACS reads a directed graph in the shortest path format (.gr)
+// of the 9th DIMACS Implementation Challenge. The input consists of
+// comment lines starting with c, a problem line "p sp n m", where n is
+// the number of vertices and m the number of arcs, followed by m arc lines
+// "a u v c" describing an edge from u to v with cost c.
+// The vertices are numbered from 1 to n in the file
+// and from 0 to n-1 in the graph.
+//
+// The edges are collected by an ImmutableBuilder, so no memory is
+// allocated for the vertices until the whole input has been read.
+// To bound the memory used for untrusted input, a graph with more than
+// 65536 vertices is rejected unless the input is at least as long as
+// the number of vertices.
+func ReadDIMACS(r io.Reader) (*Immutable, error) {
+	g, _, _, err := readDIMACS(r, "sp")
+	return g, err
+}
+
+// ReadDIMACSMaxFlow reads a maximum flow problem in the format (.max)
+// of the 1st DIMACS Implementation Challenge. The format is like the
+// one described for ReadDIMACS, but with problem line "p max n m",
+// the node lines "n s s" and "n t t" defining the source s and sink t,
+// and arc lines "a u v c" describing an edge of capacity c.
+func ReadDIMACSMaxFlow(r io.Reader) (g *Immutable, s, t int, err error) {
+	return readDIMACS(r, "max")
+}
+
+func readDIMACS(r io.Reader, problem string) (g *Immutable, s, t int, err error) {
+	in := newLineReader(r, "DIMACS")
+	var b *ImmutableBuilder
+	n, m, arcs := -1, 0, 0
+	s, t = -1, -1
+	for {
+		fields, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		switch {
+		case fields[0] == "p" && n == -1:
+			if len(fields) != 4 || fields[1] != problem {
+				return nil, 0, 0, in.errorf("expected p " + problem + " n m")
+			}
+			if n, err = in.order(fields[2]); err != nil {
+				return nil, 0, 0, err
+			}
+			if m, err = in.int(fields[3]); err != nil {
+				return nil, 0, 0, err
+			}
+			b = NewImmutableBuilder(n)
+		case n == -1:
+			return nil, 0, 0, in.errorf("missing problem line")
+		case fields[0] == "n" && problem == "max":
+			if len(fields) != 3 || fields[2] != "s" && fields[2] != "t" {
+				return nil, 0, 0, in.errorf("expected n v s or n v t")
+			}
+			v, err := in.vertex(fields[1], n, 1)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			if fields[2] == "s" {
+				s = v
+			} else {
+				t = v
+			}
+		case fields[0] == "a":
+			if len(fields) != 4 {
+				return nil, 0, 0, in.errorf("expected a u v c")
+			}
+			v, err := in.vertex(fields[1], n, 1)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			w, err := in.vertex(fields[2], n, 1)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			c, err := in.cost(fields[3])
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			b.AddCost(v, w, c)
+			arcs++
+		default:
+			return nil, 0, 0, in.errorf("unexpected " + strconv.Quote(fields[0]))
+		}
+	}
+	switch {
+	case n == -1:
+		return nil, 0, 0, in.errorf("missing problem line")
+	case arcs != m:
+		return nil, 0, 0, in.errorf("found " + strconv.Itoa(arcs) + " arcs, expected " + strconv.Itoa(m))
+	case problem == "max" && (s == -1 || t == -1):
+		return nil, 0, 0, in.errorf("missing source or sink")
+	}
+	if err := in.checkSize(n); err != nil {
+		return nil, 0, 0, err
+	}
+	return b.Finish(), s, t, nil
+}
+
+// WriteDIMACS writes g in the DIMACS shortest path format
+// described for ReadDIMACS.
+func WriteDIMACS(w io.Writer, g Iterator) error {
+	return writeDIMACS(w, g, "sp", -1, -1)
+}
+
+// WriteDIMACSMaxFlow writes the maximum flow problem for g,
+// with source s and sink t, in the DIMACS format described
+// for ReadDIMACSMaxFlow. The edge costs are used as capacities.
+func WriteDIMACSMaxFlow(w io.Writer, g Iterator, s, t int) error {
+	n := g.Order()
+	if s < 0 || s >= n {
+		panic("vertex out of range: " + strconv.Itoa(s))
+	}
+	if t < 0 || t >= n {
+		panic("vertex out of range: " + strconv.Itoa(t))
+	}
+	return writeDIMACS(w, g, "max", s, t)
+}
+
+func writeDIMACS(w io.Writer, g Iterator, problem string, s, t int) error {
+	out := bufio.NewWriter(w)
+	n := g.Order()
+	out.WriteString("p " + problem + " " + strconv.Itoa(n) + " " + strconv.Itoa(edgeCount(g)) + "\n")
+	if problem == "max" {
+		out.WriteString("n " + strconv.Itoa(s+1) + " s\n")
+		out.WriteString("n " + strconv.Itoa(t+1) + " t\n")
+	}
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			out.WriteString("a " + strconv.Itoa(v+1) + " " + strconv.Itoa(w+1) +
+				" " + strconv.FormatInt(c, 10) + "\n")
+			return
+		})
+	}
+	return out.Flush()
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadDIMACS(t *testing.T) {
+	in := `c Sample shortest path problem
+c
+p sp 4 5
+
+a 1 2 7
+a 2 3 -1
+a 3 1 2
+a 3 1 2
+a 4 4 0
+`
+	g, err := ReadDIMACS(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadDIMACS: %v", err)
+	}
+	if mess, diff := diff(g.String(), "4 [(0 1):7 (1 2):-1 2×(2 0):2 (3 3)]"); diff {
+		t.Errorf("ReadDIMACS: %s", mess)
+	}
+
+	for _, s := range []string{
+		"",
+		"a 1 2 3\n",
+		"p max 2 0\n",
+		"p sp 2\n",
+		"p sp 2 1\np sp 2 1\na 1 2 3\n",
+		"p sp 2 1\na 1 3 3\n",
+		"p sp 2 1\na 0 1 3\n",
+		"p sp 2 1\na 1 2 x\n",
+		"p sp 2 1\na 1 2\n",
+		"p sp 2 2\na 1 2 0\n",
+		"p sp 2 0\nn 1 s\n",
+		"p sp 2 0\nx\n",
+		"p sp 3000000000 0\n",
+	} {
+		if _, err := ReadDIMACS(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadDIMACS(%q): no error", s)
+		} else if !strings.HasPrefix(err.Error(), "graph: DIMACS line ") {
+			t.Errorf("ReadDIMACS(%q): bad error %q", s, err)
+		}
+	}
+
+	// Many isolated vertices need a long input.
+	if g, err := ReadDIMACS(strings.NewReader("p sp 65536 0\n")); err != nil || g.Order() != 65536 {
+		t.Errorf("ReadDIMACS: %v", err)
+	}
+	_, err = ReadDIMACS(strings.NewReader("p sp 2147483647 0\n"))
+	if mess, diff := diff(err.Error(), "graph: DIMACS: too many vertices for length"); diff {
+		t.Errorf("ReadDIMACS: %s", mess)
+	}
+}
+
+func TestReadDIMACSMaxFlow(t *testing.T) {
+	in := `c Sample max flow problem
+p max 4 5
+n 1 s
+n 4 t
+a 1 2 4
+a 1 3 2
+a 2 3 1
+a 2 4 2
+a 3 4 3
+`
+	g, s, tt, err := ReadDIMACSMaxFlow(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadDIMACSMaxFlow: %v", err)
+	}
+	if s != 0 || tt != 3 {
+		t.Errorf("ReadDIMACSMaxFlow: s = %d, t = %d; want 0, 3", s, tt)
+	}
+	if flow, _ := MaxFlow(g, s, tt); flow != 5 {
+		t.Errorf("MaxFlow: %d; want 5", flow)
+	}
+
+	for _, s := range []string{
+		"p sp 2 0\n",
+		"p max 2 0\nn 1 s\n",
+		"p max 2 0\nn 1 s\nn 3 t\n",
+		"p max 2 0\nn 1 x\n",
+	} {
+		if _, _, _, err := ReadDIMACSMaxFlow(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadDIMACSMaxFlow(%q): no error", s)
+		}
+	}
+}
+
+func TestWriteDIMACS(t *testing.T) {
+	g := New(3)
+	g.AddBothCost(0, 1, 5)
+	g.AddCost(1, 2, -3)
+	var buf bytes.Buffer
+	if err := WriteDIMACS(&buf, Sort(g)); err != nil {
+		t.Fatalf("WriteDIMACS: %v", err)
+	}
+	exp := "p sp 3 3\na 1 2 5\na 2 1 5\na 2 3 -3\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteDIMACS: %s", mess)
+	}
+
+	buf.Reset()
+	if err := WriteDIMACSMaxFlow(&buf, Sort(g), 0, 2); err != nil {
+		t.Fatalf("WriteDIMACSMaxFlow: %v", err)
+	}
+	exp = "p max 3 3\nn 1 s\nn 3 t\na 1 2 5\na 2 1 5\na 2 3 -3\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteDIMACSMaxFlow: %s", mess)
+	}
+
+	for _, g := range []Iterator{New(0), Multi{}, Sort(Multi{})} {
+		buf.Reset()
+		WriteDIMACS(&buf, g)
+		h, err := ReadDIMACS(&buf)
+		if err != nil {
+			t.Fatalf("ReadDIMACS: %v", err)
+		}
+		if mess, diff := diff(h.String(), String(g)); diff {
+			t.Errorf("DIMACS round trip: %s", mess)
+		}
+	}
+}
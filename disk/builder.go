@@ -0,0 +1,344 @@
+package disk
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// DefaultRunSize is the default number of edges that a Builder
+// keeps in memory.
+const DefaultRunSize = 1 << 22
+
+// DefaultFanIn is the default number of runs that a Builder
+// merges at a time.
+const DefaultFanIn = 64
+
+// Builder builds a graph file from a stream of edges, using external
+// memory. The edges are collected in runs of RunSize edges, which are
+// sorted in memory and written to temporary files; Finish merges
+// the runs into the graph file. If there are more than FanIn runs,
+// they are first merged into longer runs, FanIn at a time, which bounds
+// the number of open files.
+//
+// Close removes the temporary files if Finish isn't called,
+// for example after an error:
+//
+//	b := disk.NewBuilder(n)
+//	defer b.Close()
+//
+// The zero value of a Builder isn't usable; use NewBuilder.
+type Builder struct {
+	// TempDir is the directory used for temporary files.
+	// If empty, the default directory for temporary files is used.
+	TempDir string
+
+	// RunSize is the number of edges kept in memory.
+	// If not positive, DefaultRunSize is used.
+	RunSize int
+
+	// FanIn is the largest number of runs merged at a time.
+	// If less than 2, DefaultFanIn is used.
+	FanIn int
+
+	n     int
+	m     int
+	costs bool
+	run   []record
+	files []string // the temporary files holding sorted runs
+}
+
+// record is an edge from v to w with cost c.
+type record struct {
+	v, w int
+	c    int64
+}
+
+const recordSize = 24
+
+func (r record) less(s record) bool {
+	switch {
+	case r.v != s.v:
+		return r.v < s.v
+	case r.w != s.w:
+		return r.w < s.w
+	default:
+		return r.c < s.c
+	}
+}
+
+// NewBuilder returns a builder for a graph with n vertices, numbered
+// from 0 to n-1, and no edges.
+func NewBuilder(n int) *Builder {
+	if n < 0 {
+		panic("negative number of vertices: " + strconv.Itoa(n))
+	}
+	return &Builder{n: n}
+}
+
+// Add adds an edge from v to w. Duplicate edges are kept.
+func (b *Builder) Add(v, w int) error {
+	return b.AddCost(v, w, 0)
+}
+
+// AddBoth adds the two edges (v, w) and (w, v).
+// If v = w, a single loop is added.
+func (b *Builder) AddBoth(v, w int) error {
+	if err := b.AddCost(v, w, 0); err != nil || v == w {
+		return err
+	}
+	return b.AddCost(w, v, 0)
+}
+
+// AddCost adds an edge from v to w with cost c. When the number of edges
+// in memory reaches RunSize, they are sorted and written to a temporary
+// file; any error from this is returned.
+func (b *Builder) AddCost(v, w int, c int64) error {
+	if v < 0 || v >= b.n {
+		panic("vertex out of range: " + strconv.Itoa(v))
+	}
+	if w < 0 || w >= b.n {
+		panic("vertex out of range: " + strconv.Itoa(w))
+	}
+	b.run = append(b.run, record{v, w, c})
+	b.m++
+	b.costs = b.costs || c != 0
+	size := b.RunSize
+	if size <= 0 {
+		size = DefaultRunSize
+	}
+	if len(b.run) >= size {
+		return b.flush()
+	}
+	return nil
+}
+
+// flush writes the edges in memory to a temporary file, in sorted order.
+func (b *Builder) flush() (err error) {
+	sort.Slice(b.run, func(i, j int) bool { return b.run[i].less(b.run[j]) })
+	f, err := ioutil.TempFile(b.TempDir, "graph-run-")
+	if err != nil {
+		return err
+	}
+	b.files = append(b.files, f.Name())
+	out := bufio.NewWriter(f)
+	for _, r := range b.run {
+		writeRecord(out, r)
+	}
+	err = out.Flush()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	b.run = b.run[:0]
+	return err
+}
+
+// Finish writes the graph to the named file, creating it if needed,
+// and removes the temporary files. The builder must not be used
+// after Finish has been called.
+func (b *Builder) Finish(name string) (err error) {
+	defer b.cleanup()
+	fanIn := b.FanIn
+	if fanIn < 2 {
+		fanIn = DefaultFanIn
+	}
+	for len(b.files) > fanIn {
+		if err := b.mergeFiles(fanIn); err != nil {
+			return err
+		}
+	}
+
+	// The last run is merged directly from memory.
+	runs, err := openRuns(b.files)
+	if err != nil {
+		return err
+	}
+	defer closeRuns(runs)
+	sort.Slice(b.run, func(i, j int) bool { return b.run[i].less(b.run[j]) })
+	if len(b.run) > 0 {
+		runs = append(runs, &run{mem: b.run})
+	}
+	w, err := create(name, newLayout(b.n, b.m, b.costs))
+	if err != nil {
+		return err
+	}
+	if err := merge(runs, func(r record) { w.add(r.v, r.w, r.c) }); err != nil {
+		w.close()
+		return err
+	}
+	return w.close()
+}
+
+// Close removes the temporary files of the builder. It may be called
+// instead of Finish, or after it; in both cases, the builder must not
+// be used afterwards. Close returns the first error from removing
+// a file, if any.
+func (b *Builder) Close() error {
+	return b.cleanup()
+}
+
+// mergeFiles merges the first k temporary files into a new one,
+// which is added last, and removes them.
+func (b *Builder) mergeFiles(k int) (err error) {
+	runs, err := openRuns(b.files[:k])
+	if err != nil {
+		return err
+	}
+	defer closeRuns(runs)
+	f, err := ioutil.TempFile(b.TempDir, "graph-run-")
+	if err != nil {
+		return err
+	}
+	b.files = append(b.files, f.Name())
+	out := bufio.NewWriter(f)
+	err = merge(runs, func(r record) { writeRecord(out, r) })
+	if ferr := out.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range b.files[:k] {
+		os.Remove(name)
+	}
+	b.files = b.files[k:]
+	return nil
+}
+
+// cleanup removes the temporary files and returns the first error, if any.
+func (b *Builder) cleanup() (err error) {
+	for _, name := range b.files {
+		if rerr := os.Remove(name); err == nil && rerr != nil && !os.IsNotExist(rerr) {
+			err = rerr
+		}
+	}
+	b.files, b.run = nil, nil
+	return err
+}
+
+// writeRecord writes r in little-endian byte order;
+// errors are reported when out is flushed.
+func writeRecord(out *bufio.Writer, r record) {
+	var buf [recordSize]byte
+	binary.LittleEndian.PutUint64(buf[0:], uint64(r.v))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(r.w))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(r.c))
+	out.Write(buf[:])
+}
+
+// openRuns opens the named temporary files as runs.
+func openRuns(names []string) ([]*run, error) {
+	runs := make([]*run, 0, len(names)+1)
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			closeRuns(runs)
+			return nil, err
+		}
+		runs = append(runs, &run{f: f, r: bufio.NewReader(f)})
+	}
+	return runs, nil
+}
+
+// closeRuns closes the files of the runs that are still open.
+func closeRuns(runs []*run) {
+	for _, r := range runs {
+		r.close()
+	}
+}
+
+// merge calls add for each edge in the runs, in sorted order.
+func merge(runs []*run, add func(r record)) error {
+	h := &runHeap{}
+	for _, r := range runs {
+		if err := r.next(); err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		h.list = append(h.list, r)
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		r := h.list[0]
+		add(r.cur)
+		if err := r.next(); err == io.EOF {
+			heap.Pop(h)
+		} else if err != nil {
+			return err
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return nil
+}
+
+// run is a sorted sequence of edges, in memory or in a file.
+type run struct {
+	mem []record
+	f   *os.File // nil for a run in memory, or after the file is closed
+	r   *bufio.Reader
+	cur record
+}
+
+// next reads the next edge into cur; it returns io.EOF at the end of
+// the run, and then closes the file of the run.
+func (r *run) next() error {
+	if r.r == nil {
+		if len(r.mem) == 0 {
+			return io.EOF
+		}
+		r.cur, r.mem = r.mem[0], r.mem[1:]
+		return nil
+	}
+	var buf [recordSize]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		if err == io.EOF {
+			r.close()
+		}
+		if err == io.ErrUnexpectedEOF {
+			return errors.New("disk: truncated temporary file")
+		}
+		return err
+	}
+	r.cur = record{
+		v: int(binary.LittleEndian.Uint64(buf[0:])),
+		w: int(binary.LittleEndian.Uint64(buf[8:])),
+		c: int64(binary.LittleEndian.Uint64(buf[16:])),
+	}
+	return nil
+}
+
+// close closes the file of the run, if it's still open.
+func (r *run) close() {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+}
+
+// runHeap is a min-heap of runs, ordered by their current edge.
+type runHeap struct {
+	list []*run
+}
+
+func (h *runHeap) Len() int           { return len(h.list) }
+func (h *runHeap) Less(i, j int) bool { return h.list[i].cur.less(h.list[j].cur) }
+func (h *runHeap) Swap(i, j int)      { h.list[i], h.list[j] = h.list[j], h.list[i] }
+func (h *runHeap) Push(x interface{}) { h.list = append(h.list, x.(*run)) }
+
+func (h *runHeap) Pop() interface{} {
+	n := len(h.list) - 1
+	x := h.list[n]
+	h.list = h.list[:n]
+	return x
+}
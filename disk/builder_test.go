@@ -0,0 +1,126 @@
+package disk
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourbasic/graph"
+)
+
+func TestBuilder(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "g")
+
+	for _, size := range []struct{ run, fanIn int }{{0, 0}, {1, 0}, {1, 2}, {7, 3}, {100, 0}} {
+		const n = 50
+		b := NewBuilder(n)
+		b.TempDir = dir
+		b.RunSize = size.run
+		b.FanIn = size.fanIn
+		exp := graph.New(n)
+		for i := 0; i < 200; i++ {
+			v, w := rand.Intn(n), rand.Intn(n)
+			c := int64(rand.Intn(3))
+			if err := b.AddCost(v, w, c); err != nil {
+				t.Fatalf("AddCost: %v", err)
+			}
+			exp.AddCost(v, w, c)
+			if err := b.Add(v, w); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+		}
+		if err := b.AddBoth(0, n-1); err != nil {
+			t.Fatalf("AddBoth: %v", err)
+		}
+		if err := b.Finish(name); err != nil {
+			t.Fatalf("Finish: %v", err)
+		}
+		g, err := Open(name)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if g.Size() != 402 {
+			t.Errorf("Size: %d; want 402", g.Size())
+		}
+		// Each edge added with Add is a copy of an edge of exp with cost 0.
+		for v := 0; v < n; v++ {
+			exp.Visit(v, func(w int, c int64) (skip bool) {
+				if !g.Edge(v, w) {
+					t.Errorf("Edge(%d, %d): false", v, w)
+				}
+				return
+			})
+		}
+		prev := -1
+		g.Visit(0, func(w int, c int64) (skip bool) {
+			if w < prev {
+				t.Errorf("Visit: %d after %d", w, prev)
+			}
+			prev = w
+			return
+		})
+		g.Close()
+	}
+
+	// The temporary files are removed.
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("TempDir: %d files; want 1", len(files))
+	}
+}
+
+func TestBuilderString(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "g")
+
+	b := NewBuilder(4)
+	b.TempDir = dir
+	b.RunSize = 2
+	b.AddCost(2, 1, 7)
+	b.AddBoth(0, 1)
+	b.AddBoth(3, 3)
+	b.AddCost(2, 1, 3)
+	b.Add(0, 1)
+	if err := b.Finish(name); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	g, err := Open(name)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer g.Close()
+	if res, exp := graph.String(g), "4 [{0 1} (0 1) (2 1):3 (2 1):7 (3 3)]"; res != exp {
+		t.Errorf("Builder: %s; want %s", res, exp)
+	}
+}
+
+func TestBuilderClose(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	b := NewBuilder(4)
+	b.TempDir = dir
+	b.RunSize = 1
+	for v := 0; v < 4; v++ {
+		if err := b.Add(v, 3-v); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 4 {
+		t.Errorf("TempDir: %d files; want 4", len(files))
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("TempDir: %d files after Close; want 0", len(files))
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package disk provides graphs stored on disk in compressed sparse row
+// (CSR) format, for graphs too large to be kept in memory.
+//
+// A graph file is opened with Open, which maps the file into memory
+// using mmap and returns a read-only Graph implementing
+// the graph.Iterator interface. Graph files are written by WriteFile,
+// which copies any graph.Iterator, and by a Builder, which sorts a stream
+// of edges on disk and never keeps more than a fixed number of edges
+// in memory.
+//
+// On Windows and other systems without mmap, such as Plan 9 and js/wasm,
+// Open reads the whole file into memory instead.
+//
+// File format
+//
+// All numbers are stored in little-endian byte order. A graph file with
+// n vertices and m edges consists of four sections:
+//
+//  • a 32-byte header: the magic bytes "YBGC", the format version (uint32),
+//    flags (uint32), the size in bytes of a vertex number (uint32, 4 or 8),
+//    and the numbers n and m (uint64);
+//  • n+1 offsets (uint64): the edges from vertex v have indices
+//    offsets[v] to offsets[v+1]-1;
+//  • m neighbors, sorted by vertex and cost within each list;
+//  • if the flags have bit 0 set, m edge costs (int64) aligned
+//    to a multiple of 8 bytes.
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const (
+	magic      = "YBGC"
+	version    = 1
+	flagCosts  = 1 // the file includes edge costs
+	headerSize = 32
+)
+
+// Graph is a read-only graph stored in a file in CSR format.
+// The neighbors of a vertex are visited in increasing numerical order.
+type Graph struct {
+	data      []byte // the contents of the file
+	unmap     func() error
+	n, m      int
+	width     int    // the size of a vertex number in bytes
+	offsets   []byte // the offsets section
+	neighbors []byte // the neighbors section
+	costs     []byte // the costs section, or nil
+}
+
+// layout describes the sections of a graph file.
+type layout struct {
+	n, m                      int
+	width                     int
+	costs                     bool
+	offsets, neighbors, cost0 int64 // start of the sections
+	size                      int64 // the size of the file
+}
+
+func newLayout(n, m int, costs bool) layout {
+	l := layout{n: n, m: m, width: 4, costs: costs}
+	if uint64(n) > 1<<32 {
+		l.width = 8
+	}
+	l.offsets = headerSize
+	l.neighbors = l.offsets + 8*int64(n+1)
+	l.size = l.neighbors + int64(l.width)*int64(m)
+	l.cost0 = (l.size + 7) &^ 7
+	if costs {
+		l.size = l.cost0 + 8*int64(m)
+	}
+	return l
+}
+
+func (l layout) header() []byte {
+	h := make([]byte, headerSize)
+	copy(h, magic)
+	binary.LittleEndian.PutUint32(h[4:], version)
+	if l.costs {
+		binary.LittleEndian.PutUint32(h[8:], flagCosts)
+	}
+	binary.LittleEndian.PutUint32(h[12:], uint32(l.width))
+	binary.LittleEndian.PutUint64(h[16:], uint64(l.n))
+	binary.LittleEndian.PutUint64(h[24:], uint64(l.m))
+	return h
+}
+
+// Open opens the named graph file for reading. The header and the
+// offsets are validated, but not the neighbors. The file should not
+// be modified while it's open.
+func Open(name string) (*Graph, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size < headerSize {
+		return nil, formatError(name, "file too short")
+	}
+	if int64(int(size)) != size {
+		return nil, formatError(name, "file too large")
+	}
+	data, unmap, err := mapFile(f, int(size))
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGraph(name, data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	g.unmap = unmap
+	return g, nil
+}
+
+func newGraph(name string, data []byte) (*Graph, error) {
+	if string(data[:4]) != magic {
+		return nil, formatError(name, "not a graph file")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:]); v != version {
+		return nil, formatError(name, "unsupported version "+strconv.Itoa(int(v)))
+	}
+	flags := binary.LittleEndian.Uint32(data[8:])
+	if flags&^flagCosts != 0 {
+		return nil, formatError(name, "bad flags")
+	}
+	width := binary.LittleEndian.Uint32(data[12:])
+	n64 := binary.LittleEndian.Uint64(data[16:])
+	m64 := binary.LittleEndian.Uint64(data[24:])
+	// Each vertex and edge takes at least four bytes.
+	if n64 > uint64(len(data)) || m64 > uint64(len(data)) {
+		return nil, formatError(name, "wrong file size")
+	}
+	n, m := int(n64), int(m64)
+	l := newLayout(n, m, flags&flagCosts != 0)
+	if uint32(l.width) != width {
+		return nil, formatError(name, "bad vertex size")
+	}
+	if l.size != int64(len(data)) {
+		return nil, formatError(name, "wrong file size")
+	}
+	g := &Graph{
+		data:      data,
+		n:         n,
+		m:         m,
+		width:     l.width,
+		offsets:   data[l.offsets:l.neighbors],
+		neighbors: data[l.neighbors : l.neighbors+int64(l.width*m)],
+	}
+	if l.costs {
+		g.costs = data[l.cost0:]
+	}
+	prev := 0
+	for v := 0; v <= n; v++ {
+		off := binary.LittleEndian.Uint64(g.offsets[8*v:])
+		if off < uint64(prev) || off > uint64(m) || v == 0 && off != 0 || v == n && off != uint64(m) {
+			return nil, formatError(name, "bad offset for vertex "+strconv.Itoa(v))
+		}
+		prev = int(off)
+	}
+	return g, nil
+}
+
+func formatError(name, msg string) error {
+	return errors.New("disk: " + name + ": " + msg)
+}
+
+// Close releases the memory mapping of the file.
+// The graph must not be used after it has been closed.
+func (g *Graph) Close() error {
+	if g.unmap == nil {
+		return nil
+	}
+	err := g.unmap()
+	g.unmap, g.data, g.offsets, g.neighbors, g.costs = nil, nil, nil, nil, nil
+	return err
+}
+
+func (g *Graph) offset(v int) int {
+	return int(binary.LittleEndian.Uint64(g.offsets[8*v:]))
+}
+
+func (g *Graph) neighbor(i int) int {
+	if g.width == 4 {
+		return int(binary.LittleEndian.Uint32(g.neighbors[4*i:]))
+	}
+	return int(binary.LittleEndian.Uint64(g.neighbors[8*i:]))
+}
+
+func (g *Graph) cost(i int) int64 {
+	if g.costs == nil {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(g.costs[8*i:]))
+}
+
+// Order returns the number of vertices in the graph.
+func (g *Graph) Order() int {
+	return g.n
+}
+
+// Size returns the number of edges in the graph.
+func (g *Graph) Size() int {
+	return g.m
+}
+
+// Visit calls the do function for each neighbor w of v,
+// with c equal to the cost of the edge from v to w.
+// The neighbors are visited in increasing numerical order.
+// If do returns true, Visit returns immediately,
+// skipping any remaining neighbors, and returns true.
+func (g *Graph) Visit(v int, do func(w int, c int64) bool) bool {
+	for i, end := g.offset(v), g.offset(v+1); i < end; i++ {
+		if do(g.neighbor(i), g.cost(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitFrom calls the do function starting from the first neighbor w
+// for which w ≥ a, with c equal to the cost of the edge from v to w.
+// The neighbors are then visited in increasing numerical order.
+// If do returns true, VisitFrom returns immediately,
+// skipping any remaining neighbors, and returns true.
+func (g *Graph) VisitFrom(v int, a int, do func(w int, c int64) bool) bool {
+	for i, end := g.search(v, a), g.offset(v+1); i < end; i++ {
+		if do(g.neighbor(i), g.cost(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// search returns the index of the first neighbor w of v for which w ≥ a.
+func (g *Graph) search(v, a int) int {
+	start, end := g.offset(v), g.offset(v+1)
+	return start + sort.Search(end-start, func(i int) bool {
+		return a <= g.neighbor(start+i)
+	})
+}
+
+// Edge tells if there is an edge from v to w.
+func (g *Graph) Edge(v, w int) bool {
+	if v < 0 || v >= g.n {
+		return false
+	}
+	i := g.search(v, w)
+	return i < g.offset(v+1) && g.neighbor(i) == w
+}
+
+// Degree returns the number of outward directed edges from v.
+func (g *Graph) Degree(v int) int {
+	return g.offset(v+1) - g.offset(v)
+}
@@ -0,0 +1,151 @@
+package disk
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yourbasic/graph"
+	"github.com/yourbasic/graph/build"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "disk-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// roundTrip writes g to a file and opens it.
+func roundTrip(t *testing.T, dir string, g graph.Iterator) *Graph {
+	name := filepath.Join(dir, "g")
+	if err := WriteFile(name, g); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	h, err := Open(name)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return h
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	multi, _ := graph.Parse("4 [3×(0 0) 2×(0 0):5 {0 1}:-5 (1 2):7 (1 2):3 (3 2)]")
+	for _, g := range []graph.Iterator{
+		graph.New(0),
+		graph.New(3),
+		multi,
+		build.Kn(10),
+		build.Grid(5, 7).AddCost(3),
+		build.Kmn(3, 4).Add(build.DirectedEdge(0, 1)),
+	} {
+		h := roundTrip(t, dir, g)
+		if res, exp := graph.String(h), graph.String(g); res != exp {
+			t.Errorf("WriteFile: %s; want %s", res, exp)
+		}
+		s := graph.Sort(g)
+		if res, exp := graph.Check(h), graph.Check(s); res != exp {
+			t.Errorf("Check: %v; want %v", res, exp)
+		}
+		if res, exp := h.Size(), graph.Check(s).Size+graph.Check(s).Multi; res != exp {
+			t.Errorf("Size: %d; want %d", res, exp)
+		}
+		for v := 0; v < h.Order(); v++ {
+			if res, exp := h.Degree(v), s.Degree(v); res != exp {
+				t.Errorf("Degree(%d): %d; want %d", v, res, exp)
+			}
+			for w := -1; w <= h.Order(); w++ {
+				if res, exp := h.Edge(v, w), s.Edge(v, w); res != exp {
+					t.Errorf("Edge(%d, %d): %t; want %t", v, w, res, exp)
+				}
+				var res, exp []int64
+				h.VisitFrom(v, w, func(w int, c int64) (skip bool) {
+					res = append(res, int64(w), c)
+					return
+				})
+				s.VisitFrom(v, w, func(w int, c int64) (skip bool) {
+					exp = append(exp, int64(w), c)
+					return
+				})
+				if !reflect.DeepEqual(res, exp) {
+					t.Errorf("VisitFrom(%d, %d): %v; want %v", v, w, res, exp)
+				}
+			}
+		}
+		if h.Edge(-1, 0) || h.Edge(h.Order(), 0) {
+			t.Errorf("Edge: out of range vertex")
+		}
+		if err := h.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	}
+}
+
+func TestVisitAbort(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	h := roundTrip(t, dir, build.Kn(5))
+	defer h.Close()
+
+	count := 0
+	aborted := h.Visit(0, func(w int, c int64) bool {
+		count++
+		return w == 2
+	})
+	if !aborted || count != 2 {
+		t.Errorf("Visit: aborted %t after %d; want true after 2", aborted, count)
+	}
+	var res []int
+	aborted = h.VisitFrom(4, 1, func(w int, c int64) (skip bool) {
+		res = append(res, w)
+		return
+	})
+	if aborted || len(res) != 3 || res[0] != 1 || res[2] != 3 {
+		t.Errorf("VisitFrom: %v, %t; want [1 2 3], false", res, aborted)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "g")
+	if err := WriteFile(name, build.Cycle(4).AddCost(1)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(name)
+
+	if _, err := Open(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Open missing file: no error")
+	}
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), data...))
+	}
+	for i, bad := range [][]byte{
+		data[:10],
+		data[:len(data)-1],
+		append(append([]byte(nil), data...), 0),
+		corrupt(func(b []byte) []byte { b[0] = 'x'; return b }),
+		corrupt(func(b []byte) []byte { b[4] = 2; return b }),
+		corrupt(func(b []byte) []byte { b[8] = 2; return b }),
+		corrupt(func(b []byte) []byte { b[12] = 8; return b }),
+		corrupt(func(b []byte) []byte { b[16] = 5; return b }),
+		corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint64(b[24:], 1<<62); return b }),
+		corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint64(b[headerSize+8:], 9); return b }),
+		corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint64(b[headerSize+16:], 1); return b }),
+	} {
+		if err := ioutil.WriteFile(name, bad, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if g, err := Open(name); err == nil {
+			g.Close()
+			t.Errorf("Open corrupt file %d: no error", i)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/yourbasic/graph"
+)
+
+// WriteFile writes g to the named file in CSR format,
+// creating the file if needed. The neighbors of each vertex
+// are sorted in memory, one vertex at a time.
+func WriteFile(name string, g graph.Iterator) error {
+	n := g.Order()
+	m, costs := 0, false
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(_ int, c int64) (skip bool) {
+			m++
+			costs = costs || c != 0
+			return
+		})
+	}
+	w, err := create(name, newLayout(n, m, costs))
+	if err != nil {
+		return err
+	}
+	type edge struct {
+		w int
+		c int64
+	}
+	var list []edge
+	for v := 0; v < n; v++ {
+		list = list[:0]
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			list = append(list, edge{w, c})
+			return
+		})
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].w == list[j].w {
+				return list[i].c < list[j].c
+			}
+			return list[i].w < list[j].w
+		})
+		for _, e := range list {
+			w.add(v, e.w, e.c)
+		}
+	}
+	return w.close()
+}
+
+// csrWriter writes a graph file; the edges must be added in order.
+type csrWriter struct {
+	f                         *os.File
+	l                         layout
+	offsets, neighbors, costs *sectionWriter
+	v                         int // the offsets of vertices 0..v are written
+	count                     int // the number of edges added
+	err                       error
+}
+
+// create creates a graph file with the given layout.
+func create(name string, l layout) (*csrWriter, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &csrWriter{
+		f:         f,
+		l:         l,
+		offsets:   newSectionWriter(f, l.offsets),
+		neighbors: newSectionWriter(f, l.neighbors),
+		costs:     newSectionWriter(f, l.cost0),
+	}
+	if _, err := f.WriteAt(l.header(), 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	w.offsets.uint64(0)
+	return w, nil
+}
+
+// add adds an edge from v to u with cost c.
+func (w *csrWriter) add(v, u int, c int64) {
+	if v < 0 || v >= w.l.n {
+		panic("vertex out of range: " + strconv.Itoa(v))
+	}
+	if u < 0 || u >= w.l.n {
+		panic("vertex out of range: " + strconv.Itoa(u))
+	}
+	if w.count == w.l.m {
+		w.fail("too many edges")
+		return
+	}
+	for ; w.v < v; w.v++ {
+		w.offsets.uint64(uint64(w.count))
+	}
+	if w.l.width == 4 {
+		w.neighbors.uint32(uint32(u))
+	} else {
+		w.neighbors.uint64(uint64(u))
+	}
+	if w.l.costs {
+		w.costs.uint64(uint64(c))
+	}
+	w.count++
+}
+
+func (w *csrWriter) fail(msg string) {
+	if w.err == nil {
+		w.err = errors.New("disk: " + w.f.Name() + ": " + msg)
+	}
+}
+
+// close writes the remaining offsets and closes the file.
+func (w *csrWriter) close() error {
+	if w.count != w.l.m {
+		w.fail("too few edges")
+	}
+	for ; w.v < w.l.n; w.v++ {
+		w.offsets.uint64(uint64(w.count))
+	}
+	for _, s := range []*sectionWriter{w.offsets, w.neighbors, w.costs} {
+		if err := s.flush(); err != nil && w.err == nil {
+			w.err = err
+		}
+	}
+	if err := w.f.Truncate(w.l.size); err != nil && w.err == nil {
+		w.err = err
+	}
+	if err := w.f.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// sectionWriter is a buffered writer that writes f from a given offset.
+type sectionWriter struct {
+	f   *os.File
+	off int64
+	buf []byte
+	err error
+}
+
+const sectionBufferSize = 1 << 16
+
+func newSectionWriter(f *os.File, off int64) *sectionWriter {
+	return &sectionWriter{f: f, off: off}
+}
+
+func (s *sectionWriter) uint32(x uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], x)
+	s.write(b[:])
+}
+
+func (s *sectionWriter) uint64(x uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	s.write(b[:])
+}
+
+func (s *sectionWriter) write(p []byte) {
+	s.buf = append(s.buf, p...)
+	if len(s.buf) >= sectionBufferSize {
+		s.flush()
+	}
+}
+
+func (s *sectionWriter) flush() error {
+	if s.err == nil && len(s.buf) > 0 {
+		_, s.err = s.f.WriteAt(s.buf, s.off)
+		s.off += int64(len(s.buf))
+	}
+	s.buf = s.buf[:0]
+	return s.err
+}
@@ -0,0 +1,319 @@
+package graph
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Eccentricity returns the eccentricity of each vertex v in g:
+// the largest distance from v to any other vertex, where the length
+// of a path is its number of edges. The eccentricity is -1 if there is
+// a vertex that can't be reached from v.
+//
+// The implementation runs a breadth-first search from each vertex,
+// using one goroutine per available CPU. The Visit method of g must
+// therefore be safe for concurrent use; this holds for all graphs
+// in this library, as long as they aren't modified during the call.
+// The time complexity is O(|V|⋅(|E| + |V|)), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func Eccentricity(g Iterator) []int64 {
+	return eccentricities(g, singletons(g.Order()), bfsDistances)
+}
+
+// EccentricityCost is like Eccentricity, but the length of a path
+// is the sum of its edge costs. Only edges with non-negative costs
+// are included.
+//
+// The implementation runs Dijkstra's algorithm from each vertex,
+// using one goroutine per available CPU.
+// The time complexity is O(|V|⋅(|E| + |V|)⋅log|V|), where |E| is the number
+// of edges and |V| the number of vertices in the graph.
+func EccentricityCost(g Iterator) []int64 {
+	return eccentricities(g, singletons(g.Order()), costDistances)
+}
+
+// eccentricities computes the eccentricity of each vertex in the list
+// in parallel.
+func eccentricities(g Iterator, vertices []int, distances func(Iterator, int) []int64) []int64 {
+	n := len(vertices)
+	ecc := make([]int64, n)
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for i := runtime.GOMAXPROCS(0); i > 0; i-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				ecc[i] = eccentricity(distances(g, vertices[i]))
+			}
+		}()
+	}
+	wg.Wait()
+	return ecc
+}
+
+// eccentricity returns the largest distance in dist, or -1 if a
+// vertex can't be reached.
+func eccentricity(dist []int64) (e int64) {
+	for _, d := range dist {
+		if d == -1 {
+			return -1
+		}
+		if d > e {
+			e = d
+		}
+	}
+	return
+}
+
+// symmetric tells if for each edge (v, w) in g there is also an edge (w, v).
+// The time complexity is O(|E| + |V|).
+func symmetric(g *Immutable) bool {
+	// The vertices v are visited in increasing order, and so are
+	// the neighbors of each w. Hence the first neighbor of w not yet
+	// matched by an edge (v, w), next[w], must be v.
+	n := g.Order()
+	next := make([]int, n)
+	copy(next, g.offset)
+	for v := 0; v < n; v++ {
+		vertex, _ := g.neighbors(v)
+		for i, w := range vertex {
+			if i > 0 && w == vertex[i-1] {
+				continue
+			}
+			j, end := next[w], g.offset[w+1]
+			if j == end || int(g.vertex[j]) != v {
+				return false
+			}
+			for j < end && int(g.vertex[j]) == v {
+				j++
+			}
+			next[w] = j
+		}
+	}
+	for w := 0; w < n; w++ {
+		if next[w] != g.offset[w+1] {
+			return false
+		}
+	}
+	return true
+}
+
+// Diameter returns the diameter of g: the largest distance between
+// two vertices, where the length of a path is its number of edges.
+// The diameter is -1 if there are two vertices v and w such that w
+// can't be reached from v.
+//
+// If each edge (v, w) in g is matched by an edge (w, v), the implementation
+// uses the iFUB algorithm of Crescenzi et al., starting from a central
+// vertex found by a few sweeps. This typically requires only a few
+// breadth-first searches, even for large graphs. For other graphs,
+// the result is computed from all eccentricities by Eccentricity.
+//
+// In both cases, the worst-case time complexity is O(|V|⋅(|E| + |V|)),
+// where |E| is the number of edges and |V| the number of vertices in
+// the graph. If g isn't an Immutable graph, it's first copied by Sort.
+func Diameter(g Iterator) int64 {
+	h, ok := g.(*Immutable)
+	if !ok {
+		h = Sort(g)
+	}
+	if symmetric(h) {
+		return diameterIFUB(h)
+	}
+	return maxEccentricity(Eccentricity(h))
+}
+
+// DiameterCost is like Diameter, but the length of a path is the sum
+// of its edge costs. Only edges with non-negative costs are included.
+//
+// The result is computed exactly by EccentricityCost.
+func DiameterCost(g Iterator) int64 {
+	return maxEccentricity(EccentricityCost(g))
+}
+
+// maxEccentricity returns the largest eccentricity in ecc,
+// or -1 if some eccentricity is -1.
+func maxEccentricity(ecc []int64) int64 {
+	var diam int64
+	for _, e := range ecc {
+		if e == -1 {
+			return -1
+		}
+		diam = max64(diam, e)
+	}
+	return diam
+}
+
+// diameterIFUB returns the diameter of h, where each edge (v, w)
+// must be matched by an edge (w, v).
+func diameterIFUB(h *Immutable) int64 {
+	n := h.Order()
+	if n == 0 {
+		return 0
+	}
+
+	// Sweeps: search from a vertex of maximum degree, then from a farthest
+	// vertex, and then repeatedly from the vertex farthest from all previous
+	// sources. Each search gives a lower bound. Start from the vertex that
+	// minimizes the largest distance to the sources.
+	r := 0
+	for v := 0; v < h.Order(); v++ {
+		if h.Degree(v) > h.Degree(r) {
+			r = v
+		}
+	}
+	near := bfsDistances(h, r) // distance to the nearest source
+	if eccentricity(near) == -1 {
+		return -1
+	}
+	far := make([]int64, n) // distance to the farthest source
+	var lower int64
+	for i := 0; i < sweeps; i++ {
+		dist := bfsDistances(h, farthest(near))
+		for v, d := range dist {
+			if d < near[v] {
+				near[v] = d
+			}
+			far[v] = max64(far[v], d)
+		}
+		lower = max64(lower, eccentricity(dist))
+	}
+	u := 0
+	for v, d := range far {
+		if d < far[u] {
+			u = v
+		}
+	}
+
+	// Examine the vertices in order of decreasing distance from u.
+	// If all vertices at distance greater than i have eccentricity at most
+	// lower, then any two vertices at distance at most i from u are within
+	// distance 2i of each other.
+	dist := bfsDistances(h, u)
+	e := eccentricity(dist)
+	levels := make([][]int, e+1)
+	for v, d := range dist {
+		levels[d] = append(levels[d], v)
+	}
+	lower = max64(lower, e)
+	for i := e; i > 0 && lower < 2*i; i-- {
+		for _, e := range eccentricities(h, levels[i], bfsDistances) {
+			lower = max64(lower, e)
+		}
+		if lower > 2*(i-1) {
+			break
+		}
+	}
+	return lower
+}
+
+// farthest returns a vertex at the largest distance in dist.
+func farthest(dist []int64) int {
+	x := 0
+	for v, d := range dist {
+		if d > dist[x] {
+			x = v
+		}
+	}
+	return x
+}
+
+// Radius returns the radius of g: the smallest eccentricity of any vertex,
+// where the length of a path is its number of edges. The radius is -1 if
+// no vertex can reach all other vertices, and 0 if g has no vertices.
+//
+// The result is computed by Eccentricity.
+func Radius(g Iterator) int64 {
+	return minEccentricity(Eccentricity(g))
+}
+
+// RadiusCost is like Radius, but the length of a path is the sum
+// of its edge costs. Only edges with non-negative costs are included.
+//
+// The result is computed by EccentricityCost.
+func RadiusCost(g Iterator) int64 {
+	return minEccentricity(EccentricityCost(g))
+}
+
+// minEccentricity returns the smallest eccentricity in ecc, other than -1.
+// It returns -1 if all eccentricities are -1, and 0 if ecc is empty.
+func minEccentricity(ecc []int64) int64 {
+	if len(ecc) == 0 {
+		return 0
+	}
+	radius := int64(-1)
+	for _, e := range ecc {
+		if e != -1 && (radius == -1 || e < radius) {
+			radius = e
+		}
+	}
+	return radius
+}
+
+// Center returns the vertices of minimum eccentricity in g, in increasing
+// order, where an eccentricity of -1 is considered infinite.
+//
+// The result is computed by Eccentricity.
+func Center(g Iterator) []int {
+	return extremes(Eccentricity(g), smaller)
+}
+
+// CenterCost is like Center, but the length of a path is the sum
+// of its edge costs. Only edges with non-negative costs are included.
+//
+// The result is computed by EccentricityCost.
+func CenterCost(g Iterator) []int {
+	return extremes(EccentricityCost(g), smaller)
+}
+
+// Periphery returns the vertices of maximum eccentricity in g, in increasing
+// order, where an eccentricity of -1 is considered infinite.
+//
+// The result is computed by Eccentricity.
+func Periphery(g Iterator) []int {
+	return extremes(Eccentricity(g), larger)
+}
+
+// PeripheryCost is like Periphery, but the length of a path is the sum
+// of its edge costs. Only edges with non-negative costs are included.
+//
+// The result is computed by EccentricityCost.
+func PeripheryCost(g Iterator) []int {
+	return extremes(EccentricityCost(g), larger)
+}
+
+// smaller and larger compare eccentricities,
+// where -1 is considered infinite.
+func smaller(e, f int64) bool { return f == -1 && e != -1 || e != -1 && e < f }
+func larger(e, f int64) bool  { return e == -1 && f != -1 || f != -1 && e > f }
+
+// extremes returns the indices of the elements in ecc for which
+// no other element is better.
+func extremes(ecc []int64, better func(e, f int64) bool) []int {
+	res := []int{}
+	for v, e := range ecc {
+		switch {
+		case len(res) == 0 || better(e, ecc[res[0]]):
+			res = append(res[:0], v)
+		case !better(ecc[res[0]], e):
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// sweeps is the number of searches used to find a starting point for iFUB.
+const sweeps = 4
+
+func max64(x, y int64) int64 {
+	if x > y {
+		return x
+	}
+	return y
+}
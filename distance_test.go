@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEccentricity(t *testing.T) {
+	if mess, diff := diff(Eccentricity(New(0)), []int64{}); diff {
+		t.Errorf("Eccentricity: %s", mess)
+	}
+
+	// A path 0-1-2-3-4.
+	g := New(5)
+	for v := 0; v < 4; v++ {
+		g.AddBothCost(v, v+1, int64(v+1))
+	}
+	if mess, diff := diff(Eccentricity(g), []int64{4, 3, 2, 3, 4}); diff {
+		t.Errorf("Eccentricity: %s", mess)
+	}
+	if mess, diff := diff(EccentricityCost(g), []int64{10, 9, 7, 6, 10}); diff {
+		t.Errorf("EccentricityCost: %s", mess)
+	}
+	if mess, diff := diff(Diameter(g), int64(4)); diff {
+		t.Errorf("Diameter: %s", mess)
+	}
+	if mess, diff := diff(Radius(g), int64(2)); diff {
+		t.Errorf("Radius: %s", mess)
+	}
+	if mess, diff := diff(Center(g), []int{2}); diff {
+		t.Errorf("Center: %s", mess)
+	}
+	if mess, diff := diff(Periphery(g), []int{0, 4}); diff {
+		t.Errorf("Periphery: %s", mess)
+	}
+	if mess, diff := diff(DiameterCost(g), int64(10)); diff {
+		t.Errorf("DiameterCost: %s", mess)
+	}
+	if mess, diff := diff(RadiusCost(g), int64(6)); diff {
+		t.Errorf("RadiusCost: %s", mess)
+	}
+	if mess, diff := diff(CenterCost(g), []int{3}); diff {
+		t.Errorf("CenterCost: %s", mess)
+	}
+	if mess, diff := diff(PeripheryCost(g), []int{0, 4}); diff {
+		t.Errorf("PeripheryCost: %s", mess)
+	}
+
+	// Only 0 can reach all vertices.
+	g = New(3)
+	g.Add(0, 1)
+	g.AddBoth(1, 2)
+	if mess, diff := diff(Eccentricity(g), []int64{2, -1, -1}); diff {
+		t.Errorf("Eccentricity: %s", mess)
+	}
+	if mess, diff := diff(Diameter(g), int64(-1)); diff {
+		t.Errorf("Diameter: %s", mess)
+	}
+	if mess, diff := diff(Radius(g), int64(2)); diff {
+		t.Errorf("Radius: %s", mess)
+	}
+	if mess, diff := diff(Center(g), []int{0}); diff {
+		t.Errorf("Center: %s", mess)
+	}
+	if mess, diff := diff(Periphery(g), []int{1, 2}); diff {
+		t.Errorf("Periphery: %s", mess)
+	}
+	if mess, diff := diff(Radius(New(2)), int64(-1)); diff {
+		t.Errorf("Radius: %s", mess)
+	}
+	if mess, diff := diff(RadiusCost(New(0)), int64(0)); diff {
+		t.Errorf("RadiusCost: %s", mess)
+	}
+	if mess, diff := diff(DiameterCost(New(2)), int64(-1)); diff {
+		t.Errorf("DiameterCost: %s", mess)
+	}
+	if mess, diff := diff(Diameter(New(1)), int64(0)); diff {
+		t.Errorf("Diameter: %s", mess)
+	}
+
+	for i := 0; i < 100; i++ {
+		n := 1 + rand.Intn(30)
+		var g *Mutable
+		if i%2 == 0 {
+			g = randomGraph(n, n+rand.Intn(n))
+		} else {
+			g = New(n)
+			for j := rand.Intn(3 * n); j > 0; j-- {
+				g.Add(rand.Intn(n), rand.Intn(n))
+			}
+		}
+		ecc := Eccentricity(g)
+		var diam int64
+		for v := 0; v < n; v++ {
+			e := eccentricity(bfsDistances(g, v))
+			if e != ecc[v] {
+				t.Errorf("Eccentricity %v: %v", g, ecc)
+				break
+			}
+			if diam != -1 && (e == -1 || e > diam) {
+				diam = e
+			}
+		}
+		if mess, diff := diff(Diameter(g), diam); diff {
+			t.Errorf("Diameter %v: %s", g, mess)
+		}
+		if mess, diff := diff(maxEccentricity(ecc), diam); diff {
+			t.Errorf("maxEccentricity %v: %s", g, mess)
+		}
+		if h := Sort(g); symmetric(h) {
+			if mess, diff := diff(diameterIFUB(h), diam); diff {
+				t.Errorf("diameterIFUB %v: %s", g, mess)
+			}
+		}
+	}
+}
+
+func BenchmarkEccentricity(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := Sort(randomGraph(n, 5*n))
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Eccentricity(g)
+	}
+}
+
+func BenchmarkDiameter(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := Sort(randomGraph(n, 5*n))
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Diameter(g)
+	}
+}
@@ -0,0 +1,668 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions holds optional settings for WriteDOT.
+// The nil value gives a graph without name, vertex attributes or clusters,
+// in which vertex v has ID v.
+type DOTOptions struct {
+	// Name is the name of the graph. It's omitted if empty.
+	Name string
+
+	// VertexName returns the ID of vertex v.
+	// If nil, vertex v has ID v written in decimal.
+	// The IDs must be distinct. Since Graphviz has no escape
+	// for a backslash, an ID can't contain an odd number of consecutive
+	// backslashes at the end, or before a quote or a newline.
+	VertexName func(v int) string
+
+	// VertexAttrs returns the attributes of vertex v, such as
+	// "label", "color" or "shape". It may be nil.
+	VertexAttrs func(v int) map[string]string
+
+	// Clusters lists sets of vertices, each of which is written
+	// as a cluster subgraph named cluster_i.
+	Clusters [][]int
+}
+
+// WriteDOT writes a description of g in the DOT language of Graphviz.
+//
+// Edges in opposite directions with the same cost are collected into
+// undirected edges, just like in the String function. If all edges are
+// undirected, or self-loops, the result is an undirected graph with "--"
+// edges. Otherwise it's a directed graph with "->" edges, in which each
+// undirected edge has the attribute dir=both. Edges with non-zero cost
+// are labeled by the cost, and duplicate edges are written once for each
+// occurrence.
+//
+// All vertices are declared, in increasing order, before the edges.
+// WriteDOT returns an error, and writes nothing, if the name of g or
+// the ID of a vertex can't be written as a DOT ID.
+func WriteDOT(w io.Writer, g Iterator, opts *DOTOptions) error {
+	if opts == nil {
+		opts = new(DOTOptions)
+	}
+	if !isDOTQuotable(opts.Name) {
+		return fmt.Errorf("graph: DOT: can't quote graph name %q", opts.Name)
+	}
+	name := func(v int) string { return dotID(strconv.Itoa(v)) }
+	if opts.VertexName != nil {
+		ids := make([]string, g.Order())
+		for v := range ids {
+			s := opts.VertexName(v)
+			if !isDOTQuotable(s) {
+				return fmt.Errorf("graph: DOT: can't quote ID %q of vertex %d", s, v)
+			}
+			ids[v] = dotID(s)
+		}
+		name = func(v int) string { return ids[v] }
+	}
+
+	// Collect edges in opposite directions into undirected edges.
+	type dotEdge struct {
+		edge
+		both  bool // tells if the edge is undirected
+		count int  // number of occurrences
+	}
+	var list []dotEdge
+	directed := false
+	edges, count := countEdges(g)
+	for _, e := range edges {
+		c := count[e]
+		m := 0
+		if e.v < e.w {
+			back := edge{e.w, e.v, e.c}
+			m = min(c, count[back])
+			count[back] -= m
+		}
+		if m > 0 {
+			list = append(list, dotEdge{e, true, m})
+		}
+		if c-m > 0 {
+			list = append(list, dotEdge{e, false, c - m})
+			directed = directed || e.v != e.w
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	kind, op := "graph", " -- "
+	if directed {
+		kind, op = "digraph", " -> "
+	}
+	out.WriteString(kind)
+	if opts.Name != "" {
+		out.WriteString(" " + dotID(opts.Name))
+	}
+	out.WriteString(" {\n")
+	for v := 0; v < g.Order(); v++ {
+		out.WriteString("\t" + name(v))
+		if opts.VertexAttrs != nil {
+			writeDOTAttrs(out, opts.VertexAttrs(v))
+		}
+		out.WriteString(";\n")
+	}
+	for i, cluster := range opts.Clusters {
+		fmt.Fprintf(out, "\tsubgraph cluster_%d {\n", i)
+		for _, v := range cluster {
+			out.WriteString("\t\t" + name(v) + ";\n")
+		}
+		out.WriteString("\t}\n")
+	}
+	for _, e := range list {
+		attrs := make(map[string]string)
+		if e.c != 0 {
+			attrs["label"] = strconv.FormatInt(e.c, 10)
+		}
+		if directed && e.both {
+			attrs["dir"] = "both"
+		}
+		for i := 0; i < e.count; i++ {
+			out.WriteString("\t" + name(e.v) + op + name(e.w))
+			writeDOTAttrs(out, attrs)
+			out.WriteString(";\n")
+		}
+	}
+	out.WriteString("}\n")
+	return out.Flush()
+}
+
+// writeDOTAttrs writes an attribute list, sorted by name.
+func writeDOTAttrs(out *bufio.Writer, attrs map[string]string) {
+	if len(attrs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out.WriteString(" [")
+	for i, k := range keys {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(dotID(k) + "=" + dotID(attrs[k]))
+	}
+	out.WriteString("]")
+}
+
+// dotID returns s as a DOT ID, quoted if necessary.
+// Quotes in a quoted ID are escaped; Graphviz has no other escapes.
+func dotID(s string) string {
+	if isDOTName(s) && !isDOTKeyword(s) || isDOTNumeral(s) {
+		return s
+	}
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+var dotEscaper = strings.NewReplacer(`"`, `\"`)
+
+// isDOTQuotable tells if s can be written as a quoted DOT ID.
+// Graphviz pairs up consecutive backslashes, so a backslash must
+// not be left over to escape a quote or a newline.
+func isDOTQuotable(s string) bool {
+	odd := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			odd = !odd
+		case odd && (c == '"' || c == '\n'):
+			return false
+		default:
+			odd = false
+		}
+	}
+	return !odd
+}
+
+func isDOTName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(isDOTLetter(c) || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDOTLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isDOTNumeral(s string) bool {
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+	}
+	digits, dots := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '.':
+			dots++
+		case '0' <= c && c <= '9':
+			digits++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && dots <= 1
+}
+
+func isDOTKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "strict", "graph", "digraph", "node", "edge", "subgraph":
+		return true
+	}
+	return false
+}
+
+// ReadDOT reads a graph in the DOT language of Graphviz.
+// It returns the graph and the ID of each vertex.
+//
+// If the node IDs are exactly the integers 0, 1,… , n-1, vertex v gets ID v.
+// Otherwise, the vertices are numbered in order of first appearance.
+// Each edge in an undirected graph, and each edge with the attribute
+// dir=both or dir=none in a directed graph, is added in both directions,
+// except for self-loops, which are added once. If an edge label is an
+// integer, it's used as the cost of the edge. Other attributes are ignored,
+// as are ports. Edge statements may connect subgraphs, and default edge
+// attributes may be set by edge attribute statements.
+func ReadDOT(r io.Reader) (g *Immutable, names []string, err error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &dotParser{lex: dotLexer{data: data, line: 1}, ids: make(map[string]int)}
+	defer func() {
+		if e := recover(); e != nil {
+			perr, ok := e.(dotError)
+			if !ok {
+				panic(e)
+			}
+			g, names, err = nil, nil, perr
+		}
+	}()
+	p.next()
+	p.graph()
+	return p.result()
+}
+
+type dotError struct {
+	line int
+	msg  string
+}
+
+func (e dotError) Error() string {
+	return "graph: DOT line " + strconv.Itoa(e.line) + ": " + e.msg
+}
+
+// Token kinds; other tokens are represented by their character.
+const (
+	dotEOF  = 0
+	dotName = 'a' // an ID
+	dotEdge = '-' // an edge operator, -- or ->
+)
+
+type dotToken struct {
+	kind   byte
+	text   string
+	quoted bool // tells if the ID is a quoted or HTML string
+}
+
+type dotLexer struct {
+	data []byte
+	pos  int
+	line int
+}
+
+func (l *dotLexer) fail(format string, a ...interface{}) {
+	panic(dotError{l.line, fmt.Sprintf(format, a...)})
+}
+
+// scan returns the next token.
+func (l *dotLexer) scan() dotToken {
+	l.skip()
+	if l.pos >= len(l.data) {
+		return dotToken{kind: dotEOF}
+	}
+	data, start := l.data, l.pos
+	c := data[start]
+	switch {
+	case c == '-' && start+1 < len(data) && (data[start+1] == '-' || data[start+1] == '>'):
+		l.pos += 2
+		return dotToken{kind: dotEdge, text: string(data[start:l.pos])}
+	case c == '-' || c == '.' || '0' <= c && c <= '9':
+		l.pos++
+		for l.pos < len(data) && (data[l.pos] == '.' || '0' <= data[l.pos] && data[l.pos] <= '9') {
+			l.pos++
+		}
+		if s := string(data[start:l.pos]); isDOTNumeral(s) {
+			return dotToken{kind: dotName, text: s}
+		}
+		l.fail("bad numeral %q", data[start:l.pos])
+	case isDOTLetter(c):
+		for l.pos < len(data) && (isDOTLetter(data[l.pos]) || '0' <= data[l.pos] && data[l.pos] <= '9') {
+			l.pos++
+		}
+		return dotToken{kind: dotName, text: string(data[start:l.pos])}
+	case c == '"':
+		return dotToken{kind: dotName, text: l.quoted(), quoted: true}
+	case c == '<':
+		return dotToken{kind: dotName, text: l.html(), quoted: true}
+	case strings.IndexByte("{}[]=;,:+", c) >= 0:
+		l.pos++
+		return dotToken{kind: c}
+	}
+	l.fail("unexpected character %q", c)
+	return dotToken{}
+}
+
+// skip skips white space and comments.
+func (l *dotLexer) skip() {
+	data := l.data
+	for l.pos < len(data) {
+		switch c := data[l.pos]; {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			l.pos++
+		case c == '#' || c == '/' && l.pos+1 < len(data) && data[l.pos+1] == '/':
+			for l.pos < len(data) && data[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '/' && l.pos+1 < len(data) && data[l.pos+1] == '*':
+			end := strings.Index(string(data[l.pos+2:]), "*/")
+			if end < 0 {
+				l.fail("unterminated comment")
+			}
+			end += l.pos + 4
+			l.line += strings.Count(string(data[l.pos:end]), "\n")
+			l.pos = end
+		default:
+			return
+		}
+	}
+}
+
+// quoted scans a double-quoted string, in which \" stands for a quote
+// and a backslash followed by a newline is removed. As in Graphviz,
+// other backslashes are kept, and \\ doesn't escape the next character.
+func (l *dotLexer) quoted() string {
+	data := l.data
+	var buf []byte
+	for l.pos++; l.pos < len(data); l.pos++ {
+		switch c := data[l.pos]; {
+		case c == '"':
+			l.pos++
+			return string(buf)
+		case c == '\\' && l.pos+1 < len(data) && data[l.pos+1] == '"':
+			buf = append(buf, '"')
+			l.pos++
+		case c == '\\' && l.pos+1 < len(data) && data[l.pos+1] == '\\':
+			buf = append(buf, c, c)
+			l.pos++
+		case c == '\\' && l.pos+1 < len(data) && data[l.pos+1] == '\n':
+			l.line++
+			l.pos++
+		default:
+			if c == '\n' {
+				l.line++
+			}
+			buf = append(buf, c)
+		}
+	}
+	l.fail("unterminated string")
+	return ""
+}
+
+// html scans an HTML string, delimited by matching angle brackets.
+func (l *dotLexer) html() string {
+	data, start := l.data, l.pos
+	depth := 0
+	for ; l.pos < len(data); l.pos++ {
+		switch data[l.pos] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '\n':
+			l.line++
+		}
+		if depth == 0 {
+			l.pos++
+			return string(data[start+1 : l.pos-1])
+		}
+	}
+	l.fail("unterminated HTML string")
+	return ""
+}
+
+type dotParser struct {
+	lex      dotLexer
+	tok      dotToken
+	directed bool
+	ids      map[string]int // vertex numbers in order of first appearance
+	names    []string
+	edges    []edge
+}
+
+func (p *dotParser) next() {
+	p.tok = p.lex.scan()
+}
+
+// keyword tells if the current token is the given keyword.
+func (p *dotParser) keyword(s string) bool {
+	return p.tok.kind == dotName && !p.tok.quoted && strings.EqualFold(p.tok.text, s)
+}
+
+func (p *dotParser) expect(kind byte) {
+	if p.tok.kind != kind {
+		p.unexpected()
+	}
+	p.next()
+}
+
+func (p *dotParser) unexpected() {
+	switch p.tok.kind {
+	case dotEOF:
+		p.lex.fail("unexpected end of input")
+	case dotName, dotEdge:
+		p.lex.fail("unexpected %q", p.tok.text)
+	default:
+		p.lex.fail("unexpected %q", p.tok.kind)
+	}
+}
+
+// id parses an ID, including concatenated quoted strings.
+func (p *dotParser) id() string {
+	if p.tok.kind != dotName {
+		p.unexpected()
+	}
+	s, quoted := p.tok.text, p.tok.quoted
+	p.next()
+	for quoted && p.tok.kind == '+' {
+		p.next()
+		if p.tok.kind != dotName || !p.tok.quoted {
+			p.unexpected()
+		}
+		s += p.tok.text
+		p.next()
+	}
+	return s
+}
+
+// graph parses: [strict] (graph | digraph) [ID] '{' stmt_list '}'
+func (p *dotParser) graph() {
+	if p.keyword("strict") {
+		p.next()
+	}
+	switch {
+	case p.keyword("graph"):
+	case p.keyword("digraph"):
+		p.directed = true
+	default:
+		p.unexpected()
+	}
+	p.next()
+	if p.tok.kind == dotName {
+		p.id()
+	}
+	p.expect('{')
+	p.stmts(map[string]string{})
+	p.expect('}')
+	if p.tok.kind != dotEOF {
+		p.unexpected()
+	}
+}
+
+// stmts parses a statement list and returns the vertices it mentions.
+// The map holds the default edge attributes.
+func (p *dotParser) stmts(defaults map[string]string) (vertices []int) {
+	for p.tok.kind != '}' && p.tok.kind != dotEOF {
+		switch {
+		case p.keyword("graph") || p.keyword("node") || p.keyword("edge"):
+			edge := p.keyword("edge")
+			p.next()
+			attrs := p.attrs()
+			if edge {
+				for k, v := range attrs {
+					defaults[k] = v
+				}
+			}
+		case p.tok.kind == dotName && !p.keyword("subgraph"):
+			name := p.id()
+			if p.tok.kind == '=' {
+				p.next()
+				p.id() // graph attribute
+				break
+			}
+			p.port()
+			vs := []int{p.vertex(name)}
+			vertices = append(vertices, vs...)
+			if p.tok.kind == dotEdge {
+				vertices = append(vertices, p.edgeStmt(vs, defaults)...)
+			} else {
+				p.attrs()
+			}
+		default:
+			vs := p.subgraph(defaults)
+			vertices = append(vertices, vs...)
+			if p.tok.kind == dotEdge {
+				vertices = append(vertices, p.edgeStmt(vs, defaults)...)
+			}
+		}
+		if p.tok.kind == ';' || p.tok.kind == ',' {
+			p.next()
+		}
+	}
+	return
+}
+
+// subgraph parses: [subgraph [ID]] '{' stmt_list '}'
+func (p *dotParser) subgraph(defaults map[string]string) []int {
+	if p.keyword("subgraph") {
+		p.next()
+		if p.tok.kind == dotName {
+			p.id()
+		}
+	}
+	if p.tok.kind != '{' {
+		p.unexpected()
+	}
+	p.next()
+	scope := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		scope[k] = v
+	}
+	vertices := p.stmts(scope)
+	p.expect('}')
+	return vertices
+}
+
+// port skips an optional port: ':' ID [':' ID]
+func (p *dotParser) port() {
+	for i := 0; i < 2 && p.tok.kind == ':'; i++ {
+		p.next()
+		p.id()
+	}
+}
+
+// edgeStmt parses the rest of an edge statement starting with the vertices
+// in from, and returns the vertices mentioned.
+func (p *dotParser) edgeStmt(from []int, defaults map[string]string) (vertices []int) {
+	type link struct{ from, to []int }
+	var links []link
+	for p.tok.kind == dotEdge {
+		if (p.tok.text == "->") != p.directed {
+			p.lex.fail("edge operator %s doesn't match graph type", p.tok.text)
+		}
+		p.next()
+		var to []int
+		if p.tok.kind == dotName && !p.keyword("subgraph") {
+			to = []int{p.vertex(p.id())}
+			p.port()
+		} else {
+			to = p.subgraph(defaults)
+		}
+		vertices = append(vertices, to...)
+		links = append(links, link{from, to})
+		from = to
+	}
+	attrs := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		attrs[k] = v
+	}
+	for k, v := range p.attrs() {
+		attrs[k] = v
+	}
+	var cost int64
+	if label, ok := attrs["label"]; ok {
+		if c, err := strconv.ParseInt(label, 10, 64); err == nil {
+			cost = c
+		}
+	}
+	both := !p.directed || attrs["dir"] == "both" || attrs["dir"] == "none"
+	for _, l := range links {
+		for _, v := range l.from {
+			for _, w := range l.to {
+				p.edges = append(p.edges, edge{v, w, cost})
+				if both && v != w {
+					p.edges = append(p.edges, edge{w, v, cost})
+				}
+			}
+		}
+	}
+	return
+}
+
+// attrs parses zero or more attribute lists: '[' [ID ['=' ID] [;|,]]... ']'
+func (p *dotParser) attrs() map[string]string {
+	attrs := make(map[string]string)
+	for p.tok.kind == '[' {
+		p.next()
+		for p.tok.kind != ']' {
+			k, v := p.id(), "true"
+			if p.tok.kind == '=' {
+				p.next()
+				v = p.id()
+			}
+			attrs[k] = v
+			if p.tok.kind == ';' || p.tok.kind == ',' {
+				p.next()
+			}
+		}
+		p.next()
+	}
+	return attrs
+}
+
+// vertex returns the number of the vertex with the given ID.
+func (p *dotParser) vertex(name string) int {
+	v, ok := p.ids[name]
+	if !ok {
+		v = len(p.names)
+		p.ids[name] = v
+		p.names = append(p.names, name)
+	}
+	return v
+}
+
+func (p *dotParser) result() (*Immutable, []string, error) {
+	n := len(p.names)
+	// Use the IDs as vertex numbers if they are 0, 1,… , n-1.
+	perm := make([]int, n)
+	numeric := true
+	for v, name := range p.names {
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= n || strconv.Itoa(i) != name {
+			numeric = false
+			break
+		}
+		perm[v] = i
+	}
+	names := p.names
+	if numeric {
+		names = make([]string, n)
+		for v := range names {
+			names[v] = strconv.Itoa(v)
+		}
+	} else {
+		for v := range perm {
+			perm[v] = v
+		}
+	}
+	edges := make([][]neighbor, n)
+	for _, e := range p.edges {
+		v, w := perm[e.v], perm[e.w]
+		edges[v] = append(edges[v], neighbor{w, e.c})
+	}
+	return newImmutable(edges), names, nil
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// roundTripDOT writes g in the DOT language and reads it back.
+func roundTripDOT(t *testing.T, g Iterator) *Immutable {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g, nil); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+	res, _, err := ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("ReadDOT: %v\n%s", err, buf.String())
+	}
+	return res
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBothCost(1, 2, 3)
+	var buf bytes.Buffer
+	WriteDOT(&buf, g, nil)
+	exp := "graph {\n\t0;\n\t1;\n\t2;\n\t3;\n\t0 -- 1;\n\t1 -- 2 [label=3];\n}\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteDOT: %s", mess)
+	}
+
+	g.AddCost(2, 3, -1)
+	buf.Reset()
+	WriteDOT(&buf, g, &DOTOptions{
+		Name:       "my graph",
+		VertexName: func(v int) string { return "v" + strconv.Itoa(v) },
+		VertexAttrs: func(v int) map[string]string {
+			if v == 0 {
+				return map[string]string{"shape": "box", "label": `say "hi"`}
+			}
+			return nil
+		},
+		Clusters: [][]int{{0, 1}},
+	})
+	exp = `digraph "my graph" {
+	v0 [label="say \"hi\"", shape=box];
+	v1;
+	v2;
+	v3;
+	subgraph cluster_0 {
+		v0;
+		v1;
+	}
+	v0 -> v1 [dir=both];
+	v1 -> v2 [dir=both, label=3];
+	v2 -> v3 [label=-1];
+}
+`
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteDOT: %s", mess)
+	}
+
+	buf.Reset()
+	WriteDOT(&buf, Multi{}, nil)
+	exp = `digraph {
+	0;
+	1;
+	2;
+	0 -> 0;
+	0 -> 0;
+	0 -> 0;
+	0 -> 0 [label=5];
+	0 -> 0 [label=5];
+	0 -> 1 [dir=both, label=5];
+	0 -> 1 [dir=both, label=5];
+	0 -> 1 [dir=both, label=5];
+	0 -> 1 [dir=both, label=5];
+	0 -> 1 [label=5];
+	0 -> 1 [label=7];
+}
+`
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteDOT: %s", mess)
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	// Round trips.
+	g := New(5)
+	g.AddBoth(0, 1)
+	g.AddBothCost(1, 2, Max)
+	g.AddCost(3, 2, Min)
+	g.Add(4, 4)
+	for _, h := range []Iterator{New(0), New(3), g, Multi{}, Sort(g), Transpose(g)} {
+		if res := roundTripDOT(t, h); !Equal(res, h) {
+			t.Errorf("ReadDOT: %v; want %v", res, h)
+		}
+	}
+
+	// IDs with quotes and backslashes.
+	ids := []string{`a\b`, `"`, `b\\"c`, `\\`, `\n`}
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, Sort(g), &DOTOptions{VertexName: func(v int) string { return ids[v] }}); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+	res, names, err := ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("ReadDOT: %v", err)
+	}
+	if !Equal(res, g) {
+		t.Errorf("ReadDOT: %v; want %v", res, g)
+	}
+	if mess, diff := diff(names, ids); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	res, names, err = ReadDOT(strings.NewReader(`graph { "a\\\"b" -- "c\d\
+e\\" }`))
+	if err != nil {
+		t.Fatalf("ReadDOT: %v", err)
+	}
+	if mess, diff := diff(names, []string{`a\\"b`, `c\de\\`}); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	for _, id := range []string{`a\`, `b\"c`, `\\\"`, "c\\\n"} {
+		buf.Reset()
+		opts := &DOTOptions{VertexName: func(v int) string { return id }}
+		if err := WriteDOT(&buf, New(1), opts); err == nil || buf.Len() > 0 {
+			t.Errorf("WriteDOT(%q): err = %v, wrote %q", id, err, buf.String())
+		}
+	}
+	if err := WriteDOT(&buf, New(0), &DOTOptions{Name: `G\`}); err == nil {
+		t.Errorf("WriteDOT: no error for graph name %q", `G\`)
+	}
+
+	src := `/* A small graph. */
+strict digraph G {
+	# preprocessor output is ignored
+	graph [rankdir=LR]; node [shape=circle]
+	rankdir = LR
+	edge [label=2]
+	a -> b -> c // a path
+	"a" -> {d; e} [label = "4"]
+	subgraph s { edge [label="x"]; c:n -> d:port:s }
+	e -> a [dir=both, color=<<b>red</b>>]
+	"f" + "g";
+}`
+	res, names, err = ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadDOT: %v", err)
+	}
+	if mess, diff := diff(res.String(), "6 [(0 1):2 (0 3):4 {0 4}:2 (0 4):4 (1 2):2 (2 3)]"); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	if mess, diff := diff(names, []string{"a", "b", "c", "d", "e", "fg"}); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+
+	// Numeric IDs are used as vertex numbers only if they are 0..n-1.
+	res, names, _ = ReadDOT(strings.NewReader("graph { 2 -- 0; 1 }"))
+	if mess, diff := diff(res.String(), "3 [{0 2}]"); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	if mess, diff := diff(names, []string{"0", "1", "2"}); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	res, names, _ = ReadDOT(strings.NewReader("graph { 2 -- 3 }"))
+	if mess, diff := diff(res.String(), "2 [{0 1}]"); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+	if mess, diff := diff(names, []string{"2", "3"}); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+
+	for _, src := range []string{
+		"",
+		"graph",
+		"graph {",
+		"graph { a -> b }",
+		"digraph { a -- b }",
+		"graph { a -- }",
+		"graph { a [label=] }",
+		`graph { "a }`,
+		"graph { /* a }",
+		"graph { - }",
+		"graph { a } b",
+		"tree { }",
+	} {
+		if _, _, err := ReadDOT(strings.NewReader(src)); err == nil {
+			t.Errorf("ReadDOT(%q): no error", src)
+		}
+	}
+
+	_, _, err = ReadDOT(strings.NewReader("graph {\n\ta -- b\n\t]\n}"))
+	if mess, diff := diff(err.Error(), `graph: DOT line 3: unexpected ']'`); diff {
+		t.Errorf("ReadDOT: %s", mess)
+	}
+}
@@ -3,6 +3,7 @@ package graph_test
 import (
 	"fmt"
 	"github.com/yourbasic/graph"
+	"os"
 )
 
 // Build a plain graph and visit all of its edges.
@@ -156,6 +157,84 @@ func ExampleShortestPath() {
 	// path: [0 3 4 1 2 5] length: 10
 }
 
+// Schedule exams so that no student has two exams at the same time.
+func ExampleChromaticNumber() {
+	// Vertices are exams; an edge connects two exams
+	// that have at least one student in common.
+	g := graph.New(5)
+	g.AddBoth(0, 1) //  0 -- 1
+	g.AddBoth(0, 2) //  | \  |
+	g.AddBoth(0, 3) //  2 -- 3 -- 4
+	g.AddBoth(1, 3)
+	g.AddBoth(2, 3)
+	g.AddBoth(3, 4)
+
+	// Each color corresponds to a time slot.
+	k, colors := graph.ChromaticNumber(g)
+	fmt.Println("time slots:", k)
+	fmt.Println("colors:", colors)
+	// Output:
+	// time slots: 3
+	// colors: [1 2 2 0 1]
+}
+
+// Detect duplicate graphs by comparing canonical forms.
+func ExampleCanonical() {
+	g1 := graph.New(4)
+	g1.AddBoth(0, 1) //  0 -- 1 -- 2 -- 3
+	g1.AddBoth(1, 2)
+	g1.AddBoth(2, 3)
+
+	g2 := graph.New(4)
+	g2.AddBoth(3, 0) //  1 -- 3 -- 0 -- 2
+	g2.AddBoth(0, 2)
+	g2.AddBoth(1, 3)
+
+	_, c1 := graph.Canonical(g1)
+	_, c2 := graph.Canonical(g2)
+	fmt.Println(graph.Equal(g1, g2), graph.Equal(c1, c2))
+	fmt.Println(c1.String() == c2.String())
+	// Output:
+	// false true
+	// true
+}
+
+// Rank the vertices of a small graph by betweenness centrality.
+func ExampleBetweenness() {
+	g := graph.New(5)
+	g.AddBoth(0, 1) //  0 -- 1 -- 2 -- 3
+	g.AddBoth(1, 2) //       |
+	g.AddBoth(2, 3) //       4
+	g.AddBoth(1, 4)
+	fmt.Println(graph.Betweenness(g))
+	// Output: [0 10 6 0 0]
+}
+
+// Find the communities in a graph with two dense parts.
+func ExampleLouvain() {
+	// Two triangles joined by an edge.
+	//
+	//  0         4
+	//  | \     / |
+	//  |  2 - 3  |
+	//  | /     \ |
+	//  1         5
+	g := graph.New(6)
+	g.AddBothCost(0, 1, 1)
+	g.AddBothCost(0, 2, 1)
+	g.AddBothCost(1, 2, 1)
+	g.AddBothCost(2, 3, 1)
+	g.AddBothCost(3, 4, 1)
+	g.AddBothCost(3, 5, 1)
+	g.AddBothCost(4, 5, 1)
+	communities := graph.Louvain(g)
+	fmt.Println(communities)
+	fmt.Printf("%.3f\n", graph.Modularity(g, communities))
+	// Output:
+	// [[0 1 2] [3 4 5]]
+	// 0.357
+}
+
 // Find the strongly connected components in a directed graph.
 func ExampleStrongComponents() {
 	g := graph.New(6)
@@ -182,3 +261,38 @@ func ExampleComponents() {
 	fmt.Println(graph.Components(g))
 	// Output: [[0 1 2 5] [3 4]]
 }
+
+// Write a graph in the DOT language of Graphviz.
+func ExampleWriteDOT() {
+	g := graph.New(3)
+	g.AddBoth(0, 1)
+	g.AddCost(1, 2, 5)
+	graph.WriteDOT(os.Stdout, g, &graph.DOTOptions{
+		Name: "G",
+		VertexAttrs: func(v int) map[string]string {
+			return map[string]string{"label": string(rune('a' + v))}
+		},
+	})
+	// Output:
+	// digraph G {
+	// 	0 [label=a];
+	// 	1 [label=b];
+	// 	2 [label=c];
+	// 	0 -> 1 [dir=both];
+	// 	1 -> 2 [label=5];
+	// }
+}
+
+// Turn the string representation of a graph back into a graph.
+func ExampleParse() {
+	g, err := graph.Parse("4 [{0 1} (2 1):8 2×(3 3)]")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(g.Order(), g.Degree(1), g.Degree(3))
+	fmt.Println(g)
+	// Output:
+	// 4 1 2
+	// 4 [{0 1} (2 1):8 2×(3 3)]
+}
@@ -82,16 +82,45 @@ type edge struct {
 // String returns a description of g with two elements:
 // the number of vertices, followed by a sorted list of all edges.
 func String(g Iterator) string {
+	n := g.Order()
+	edges, count := countEdges(g)
+	// Build the string.
+	var buf []byte
+	buf = strconv.AppendInt(buf, int64(n), 10)
+	buf = append(buf, " ["...)
+	for _, e := range edges {
+		c := count[e]
+		if e.v < e.w {
+			// Collect edges in opposite directions into an undirected edge.
+			back := edge{e.w, e.v, e.c}
+			m := min(c, count[back])
+			count[back] -= m
+			buf = appendEdge(buf, e, m, true)
+			buf = appendEdge(buf, e, c-m, false)
+		} else {
+			buf = appendEdge(buf, e, c, false)
+		}
+	}
+	if len(edges) > 0 {
+		buf = buf[:len(buf)-1] // Remove trailing ' '.
+	}
+	buf = append(buf, ']')
+	return string(buf)
+}
+
+// countEdges returns the distinct edges of g, sorted lexicographically
+// on (v, w, c), and the number of times each edge occurs in g.
+func countEdges(g Iterator) (edges []edge, count map[edge]int) {
 	n := g.Order()
 	// This may be a multigraph, so we look for duplicates by counting.
-	count := make(map[edge]int)
+	count = make(map[edge]int)
 	for v := 0; v < n; v++ {
 		g.Visit(v, func(w int, c int64) (skip bool) {
 			count[edge{v, w, c}]++
 			return
 		})
 	}
-	edges := make([]edge, 0, len(count))
+	edges = make([]edge, 0, len(count))
 	for e := range count {
 		edges = append(edges, e)
 	}
@@ -108,28 +137,7 @@ func String(g Iterator) string {
 			return edges[i].v < edges[j].v
 		}
 	})
-	// Build the string.
-	var buf []byte
-	buf = strconv.AppendInt(buf, int64(n), 10)
-	buf = append(buf, " ["...)
-	for _, e := range edges {
-		c := count[e]
-		if e.v < e.w {
-			// Collect edges in opposite directions into an undirected edge.
-			back := edge{e.w, e.v, e.c}
-			m := min(c, count[back])
-			count[back] -= m
-			buf = appendEdge(buf, e, m, true)
-			buf = appendEdge(buf, e, c-m, false)
-		} else {
-			buf = appendEdge(buf, e, c, false)
-		}
-	}
-	if len(edges) > 0 {
-		buf = buf[:len(buf)-1] // Remove trailing ' '.
-	}
-	buf = append(buf, ']')
-	return string(buf)
+	return
 }
 
 func appendEdge(buf []byte, e edge, count int, bi bool) []byte {
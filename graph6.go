@@ -0,0 +1,389 @@
+package graph
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The graph6, sparse6 and digraph6 formats are compact ASCII encodings
+// of graphs, defined by Brendan McKay for the nauty package. Edge costs
+// are not part of the formats: they are ignored when encoding, and the
+// edges of a decoded graph have zero cost.
+//
+// An encoding consists of the number of vertices followed by a bit vector,
+// with each group of six bits stored in a byte in the range 63 to 126.
+// The graph6 format describes the upper triangle of the adjacency matrix
+// of an undirected simple graph. The sparse6 format, which starts with ':',
+// describes a list of edges of an undirected graph that may contain loops
+// and multiple edges. The digraph6 format, which starts with '&', describes
+// the full adjacency matrix of a directed graph that may contain loops.
+
+// The largest number of vertices accepted by the decoders.
+const maxN6 = 1<<31 - 1
+
+// The largest number of vertices in a sparse6 encoding that is accepted
+// regardless of the length of the encoding.
+const maxSparse6Isolated = 1 << 16
+
+const (
+	graph6Header   = ">>graph6<<"
+	sparse6Header  = ">>sparse6<<"
+	digraph6Header = ">>digraph6<<"
+)
+
+// EncodeGraph6 returns the graph6 encoding of g, which must be
+// an undirected graph without self-loops or multiple edges.
+func EncodeGraph6(g Iterator) (string, error) {
+	h := Sort(g)
+	n := h.Order()
+	b := newBits6(appendN6(nil, n))
+	b.grow(n * (n - 1) / 2)
+	for v := 0; v < n; v++ {
+		vertex, _ := h.neighbors(v)
+		for i := range vertex {
+			w := int(vertex[i])
+			switch {
+			case v == w:
+				return "", &formatError{"graph6", noLine, "self-loop at " + strconv.Itoa(v)}
+			case i > 0 && vertex[i-1] == vertex[i]:
+				return "", &formatError{"graph6", noLine, "multiple edges " + edgeName(v, w)}
+			case !h.Edge(w, v):
+				return "", &formatError{"graph6", noLine, "directed edge " + edgeName(v, w)}
+			case v < w:
+				b.set(w*(w-1)/2 + v)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// DecodeGraph6 returns the graph described by the graph6 encoding s,
+// which may start with the header ">>graph6<<".
+func DecodeGraph6(s string) (*Immutable, error) {
+	n, bits, err := decodeN6(strings.TrimPrefix(s, graph6Header), "graph6")
+	if err != nil {
+		return nil, err
+	}
+	if err := bits.checkLen(int64(n)*int64(n-1)/2, "graph6"); err != nil {
+		return nil, err
+	}
+	edges := make([][]neighbor, n)
+	for w, i := 1, 0; w < n; w++ {
+		for v := 0; v < w; v, i = v+1, i+1 {
+			if bits.bit(i) == 1 {
+				edges[v] = append(edges[v], neighbor{w, 0})
+				edges[w] = append(edges[w], neighbor{v, 0})
+			}
+		}
+	}
+	return newImmutable(edges), nil
+}
+
+// EncodeDigraph6 returns the digraph6 encoding of g,
+// which must not contain multiple edges.
+func EncodeDigraph6(g Iterator) (string, error) {
+	h := Sort(g)
+	n := h.Order()
+	b := newBits6(appendN6([]byte{'&'}, n))
+	b.grow(n * n)
+	for v := 0; v < n; v++ {
+		vertex, _ := h.neighbors(v)
+		for i := range vertex {
+			w := int(vertex[i])
+			if i > 0 && vertex[i-1] == vertex[i] {
+				return "", &formatError{"digraph6", noLine, "multiple edges " + edgeName(v, w)}
+			}
+			b.set(v*n + w)
+		}
+	}
+	return b.String(), nil
+}
+
+// DecodeDigraph6 returns the graph described by the digraph6 encoding s,
+// which may start with the header ">>digraph6<<".
+func DecodeDigraph6(s string) (*Immutable, error) {
+	s = strings.TrimPrefix(s, digraph6Header)
+	if !strings.HasPrefix(s, "&") {
+		return nil, &formatError{"digraph6", noLine, "missing &"}
+	}
+	n, bits, err := decodeN6(s[1:], "digraph6")
+	if err != nil {
+		return nil, err
+	}
+	if err := bits.checkLen(int64(n)*int64(n), "digraph6"); err != nil {
+		return nil, err
+	}
+	edges := make([][]neighbor, n)
+	for v, i := 0, 0; v < n; v++ {
+		for w := 0; w < n; w, i = w+1, i+1 {
+			if bits.bit(i) == 1 {
+				edges[v] = append(edges[v], neighbor{w, 0})
+			}
+		}
+	}
+	return newImmutable(edges), nil
+}
+
+// EncodeSparse6 returns the sparse6 encoding of g, which must be
+// an undirected graph: for each edge (v, w), v ≠ w, there must be
+// an edge (w, v), and these edges must occur the same number of times.
+func EncodeSparse6(g Iterator) (string, error) {
+	h := Sort(g)
+	n := h.Order()
+	// The edges (v, w), v ≥ w, sorted by v and then by w.
+	var list []edge
+	count := make(map[edge]int)
+	for v := 0; v < n; v++ {
+		vertex, _ := h.neighbors(v)
+		for _, w := range vertex {
+			count[edge{v, int(w), 0}]++
+			if int(w) <= v {
+				list = append(list, edge{v, int(w), 0})
+			}
+		}
+	}
+	for e, k := range count {
+		if count[edge{e.w, e.v, 0}] != k {
+			return "", &formatError{"sparse6", noLine, "directed edge " + edgeName(e.v, e.w)}
+		}
+	}
+
+	k := 0 // the number of bits needed to represent n-1
+	for x := n - 1; x > 0; x >>= 1 {
+		k++
+	}
+	b := newBits6(appendN6([]byte{':'}, n))
+	cur := 0
+	for _, e := range list {
+		switch {
+		case e.v == cur:
+			b.add(0, 1)
+		case e.v == cur+1:
+			b.add(1, 1)
+			cur = e.v
+		default:
+			b.add(1, 1)
+			b.add(e.v, k)
+			b.add(0, 1)
+			cur = e.v
+		}
+		b.add(e.w, k)
+	}
+	// Pad with ones, unless this would be read as an edge to vertex n-1.
+	pad := (6 - b.n%6) % 6
+	if k < 6 && n == 1<<uint(k) && pad >= k && cur < n-1 {
+		b.add(0, 1)
+		pad--
+	}
+	b.add(1<<uint(pad)-1, pad)
+	return b.String(), nil
+}
+
+// DecodeSparse6 returns the graph described by the sparse6 encoding s,
+// which may start with the header ">>sparse6<<".
+// An undirected edge {v, w}, v ≠ w, is represented by the two edges
+// (v, w) and (w, v), while a loop at v is represented by one edge (v, v).
+//
+// To limit the memory used for short inputs, the number of vertices
+// may exceed 65536 only if it's at most one more than the number
+// of bits in the edge list.
+func DecodeSparse6(s string) (*Immutable, error) {
+	s = strings.TrimPrefix(s, sparse6Header)
+	if !strings.HasPrefix(s, ":") {
+		return nil, &formatError{"sparse6", noLine, "missing :"}
+	}
+	n, bits, err := decodeN6(s[1:], "sparse6")
+	if err != nil {
+		return nil, err
+	}
+	if n > maxSparse6Isolated && n > bits.len()+1 {
+		return nil, &formatError{"sparse6", noLine, "too many vertices for length"}
+	}
+	k := 0
+	for x := n - 1; x > 0; x >>= 1 {
+		k++
+	}
+	edges := make([][]neighbor, n)
+	v := 0
+	for i := 0; i+1+k <= bits.len(); i += 1 + k {
+		if bits.bit(i) == 1 {
+			v++
+		}
+		x := 0
+		for j := 1; j <= k; j++ {
+			x = x<<1 | bits.bit(i+j)
+		}
+		if x >= n || v >= n {
+			break // padding
+		}
+		if x > v {
+			v = x
+			continue
+		}
+		edges[v] = append(edges[v], neighbor{x, 0})
+		if x != v {
+			edges[x] = append(edges[x], neighbor{v, 0})
+		}
+	}
+	return newImmutable(edges), nil
+}
+
+// Graph6Reader reads a sequence of graphs in graph6, sparse6 or digraph6
+// format, one graph per line. The format of each line is determined by its
+// first character, and the line may start with a header such as ">>graph6<<".
+// Empty lines are skipped.
+type Graph6Reader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewGraph6Reader returns a new reader that reads from r.
+func NewGraph6Reader(r io.Reader) *Graph6Reader {
+	return &Graph6Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next graph; at the end of input it returns io.EOF.
+func (r *Graph6Reader) Read() (*Immutable, error) {
+	for {
+		s, err := r.r.ReadString('\n')
+		if err == io.EOF && s != "" {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.line++
+		s = strings.TrimRight(s, "\r\n")
+		if s == "" {
+			continue
+		}
+		var g *Immutable
+		switch {
+		case strings.HasPrefix(s, sparse6Header), strings.HasPrefix(s, ":"):
+			g, err = DecodeSparse6(s)
+		case strings.HasPrefix(s, digraph6Header), strings.HasPrefix(s, "&"):
+			g, err = DecodeDigraph6(s)
+		default:
+			g, err = DecodeGraph6(s)
+		}
+		if err, ok := err.(*formatError); ok {
+			err.line = r.line
+			return nil, err
+		}
+		return g, nil
+	}
+}
+
+func edgeName(v, w int) string {
+	return "(" + strconv.Itoa(v) + " " + strconv.Itoa(w) + ")"
+}
+
+// appendN6 appends the encoding of the number of vertices n.
+func appendN6(buf []byte, n int) []byte {
+	switch {
+	case n <= 62:
+		return append(buf, byte(n+63))
+	case n <= 258047:
+		return append(buf, 126, byte(n>>12+63), byte(n>>6&63+63), byte(n&63+63))
+	default:
+		buf = append(buf, 126, 126)
+		for shift := uint(30); ; shift -= 6 {
+			buf = append(buf, byte(n>>shift&63+63))
+			if shift == 0 {
+				return buf
+			}
+		}
+	}
+}
+
+// decodeN6 decodes the number of vertices at the start of s,
+// and returns it together with the rest of s.
+func decodeN6(s string, format string) (n int, rest bits6, err error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 63 || s[i] > 126 {
+			return 0, "", &formatError{format, noLine, "bad character " + strconv.QuoteRune(rune(s[i]))}
+		}
+	}
+	size := 1
+	switch {
+	case strings.HasPrefix(s, "~~"):
+		s, size = s[2:], 6
+	case strings.HasPrefix(s, "~"):
+		s, size = s[1:], 3
+	}
+	if len(s) < size {
+		return 0, "", &formatError{format, noLine, "missing number of vertices"}
+	}
+	var x int64
+	for i := 0; i < size; i++ {
+		x = x<<6 | int64(s[i]-63)
+	}
+	if x > maxN6 {
+		return 0, "", &formatError{format, noLine, "too many vertices"}
+	}
+	return int(x), bits6(s[size:]), nil
+}
+
+// bits6 is a bit vector stored six bits per byte, as in the formats.
+type bits6 string
+
+func (b bits6) len() int {
+	return 6 * len(b)
+}
+
+func (b bits6) bit(i int) int {
+	return int(b[i/6]-63) >> uint(5-i%6) & 1
+}
+
+func (b bits6) checkLen(n int64, format string) error {
+	if int64(len(b)) != (n+5)/6 {
+		return &formatError{format, noLine, "wrong length"}
+	}
+	return nil
+}
+
+// bitWriter appends a bit vector, six bits per byte, to buf.
+// The bytes of the vector hold the raw bits; String adds 63.
+type bitWriter struct {
+	buf   []byte
+	start int // the index in buf of the bit vector
+	n     int // the number of bits
+}
+
+func newBits6(buf []byte) *bitWriter {
+	return &bitWriter{buf: buf, start: len(buf)}
+}
+
+// grow adds n zero bits.
+func (b *bitWriter) grow(n int) {
+	b.n += n
+	for len(b.buf) < b.start+(b.n+5)/6 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// set sets bit i to one.
+func (b *bitWriter) set(i int) {
+	b.buf[b.start+i/6] |= 1 << uint(5-i%6)
+}
+
+// add adds the k lowest bits of x, most significant bit first.
+func (b *bitWriter) add(x, k int) {
+	for j := k - 1; j >= 0; j-- {
+		i := b.n
+		b.grow(1)
+		if x>>uint(j)&1 == 1 {
+			b.set(i)
+		}
+	}
+}
+
+func (b *bitWriter) String() string {
+	buf := append([]byte(nil), b.buf...)
+	for i := b.start; i < len(buf); i++ {
+		buf[i] += 63
+	}
+	return string(buf)
+}
@@ -0,0 +1,207 @@
+package graph_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourbasic/graph"
+	"github.com/yourbasic/graph/build"
+)
+
+func TestGraph6Examples(t *testing.T) {
+	// Examples from the nauty documentation.
+	g, err := graph.DecodeGraph6("DQc")
+	if err != nil {
+		t.Fatalf("DecodeGraph6: %v", err)
+	}
+	if res, exp := g.String(), "5 [{0 2} {0 4} {1 3} {3 4}]"; res != exp {
+		t.Errorf("DecodeGraph6: %s; want %s", res, exp)
+	}
+	if s, _ := graph.EncodeGraph6(g); s != "DQc" {
+		t.Errorf("EncodeGraph6: %s; want DQc", s)
+	}
+
+	g, err = graph.DecodeSparse6(">>sparse6<<:Fa@x^")
+	if err != nil {
+		t.Fatalf("DecodeSparse6: %v", err)
+	}
+	if res, exp := g.String(), "7 [{0 1} {0 2} {1 2} {5 6}]"; res != exp {
+		t.Errorf("DecodeSparse6: %s; want %s", res, exp)
+	}
+	if s, _ := graph.EncodeSparse6(g); s != ":Fa@x^" {
+		t.Errorf("EncodeSparse6: %s; want :Fa@x^", s)
+	}
+
+	g, err = graph.DecodeDigraph6("&DI?AO?")
+	if err != nil {
+		t.Fatalf("DecodeDigraph6: %v", err)
+	}
+	if res, exp := g.String(), "5 [(0 2) (0 4) (3 1) (3 4)]"; res != exp {
+		t.Errorf("DecodeDigraph6: %s; want %s", res, exp)
+	}
+	if s, _ := graph.EncodeDigraph6(g); s != "&DI?AO?" {
+		t.Errorf("EncodeDigraph6: %s; want &DI?AO?", s)
+	}
+}
+
+func TestGraph6RoundTrip(t *testing.T) {
+	var graphs []*build.Virtual
+	for n := 0; n <= 70; n++ {
+		graphs = append(graphs, build.Kn(n), build.Circulant(n, 1, 3))
+	}
+	for n := 0; n <= 6; n++ {
+		graphs = append(graphs, build.Hyper(n))
+	}
+	for _, g := range graphs {
+		exp := g.String()
+		s, err := graph.EncodeGraph6(g)
+		if err != nil {
+			t.Fatalf("EncodeGraph6(%s): %v", exp, err)
+		}
+		h, err := graph.DecodeGraph6(s)
+		if err != nil {
+			t.Fatalf("DecodeGraph6(%s): %v", s, err)
+		}
+		if res := h.String(); res != exp {
+			t.Errorf("graph6: %s; want %s", res, exp)
+		}
+
+		s, err = graph.EncodeSparse6(g)
+		if err != nil {
+			t.Fatalf("EncodeSparse6(%s): %v", exp, err)
+		}
+		if h, err = graph.DecodeSparse6(s); err != nil {
+			t.Fatalf("DecodeSparse6(%s): %v", s, err)
+		}
+		if res := h.String(); res != exp {
+			t.Errorf("sparse6: %s; want %s", res, exp)
+		}
+
+		s, err = graph.EncodeDigraph6(g)
+		if err != nil {
+			t.Fatalf("EncodeDigraph6(%s): %v", exp, err)
+		}
+		if h, err = graph.DecodeDigraph6(s); err != nil {
+			t.Fatalf("DecodeDigraph6(%s): %v", s, err)
+		}
+		if res := h.String(); res != exp {
+			t.Errorf("digraph6: %s; want %s", res, exp)
+		}
+	}
+
+	// Loops and multiple edges in sparse6; every small n and padding case.
+	for n := 1; n <= 20; n++ {
+		g := graph.New(n)
+		g.Add(n-1, n-1)
+		g.AddBoth(0, n-1)
+		s, err := graph.EncodeSparse6(g)
+		if err != nil {
+			t.Fatalf("EncodeSparse6: %v", err)
+		}
+		h, err := graph.DecodeSparse6(s)
+		if err != nil {
+			t.Fatalf("DecodeSparse6(%s): %v", s, err)
+		}
+		if res, exp := h.String(), g.String(); res != exp {
+			t.Errorf("sparse6 %s: %s; want %s", s, res, exp)
+		}
+	}
+	multi, _ := graph.Parse("4 [2×(0 0) 3×{0 1} {1 3} {2 3}]")
+	s, _ := graph.EncodeSparse6(multi)
+	if h, _ := graph.DecodeSparse6(s); h.String() != multi.String() {
+		t.Errorf("sparse6 multigraph: %s; want %s", h, multi)
+	}
+
+	// Large graphs need a longer size prefix.
+	for _, n := range []int{100000, 300000} {
+		s, _ = graph.EncodeSparse6(build.Cycle(n))
+		h, err := graph.DecodeSparse6(s)
+		if err != nil {
+			t.Fatalf("DecodeSparse6: %v", err)
+		}
+		if h.Order() != n || graph.Check(h).Size != 2*n || !h.Edge(0, n-1) || !h.Edge(n/2, n/2+1) {
+			t.Errorf("sparse6: bad cycle of length %d", n)
+		}
+	}
+	if !strings.HasPrefix(s, ":~~") {
+		t.Errorf("sparse6: %q; want prefix :~~", s[:10])
+	}
+}
+
+func TestGraph6Errors(t *testing.T) {
+	directed := graph.New(2)
+	directed.Add(0, 1)
+	loop := graph.New(1)
+	loop.Add(0, 0)
+	multi, _ := graph.Parse("2 [2×{0 1}]")
+	for _, g := range []graph.Iterator{directed, loop, multi} {
+		if _, err := graph.EncodeGraph6(g); err == nil {
+			t.Errorf("EncodeGraph6(%v): no error", g)
+		}
+	}
+	if _, err := graph.EncodeSparse6(directed); err == nil {
+		t.Errorf("EncodeSparse6(%v): no error", directed)
+	}
+	if _, err := graph.EncodeDigraph6(multi); err == nil {
+		t.Errorf("EncodeDigraph6(%v): no error", multi)
+	}
+
+	for _, s := range []string{"", "D", "DQcc", "DQ\x01", "~", "~~~~~~~~"} {
+		if _, err := graph.DecodeGraph6(s); err == nil {
+			t.Errorf("DecodeGraph6(%q): no error", s)
+		}
+	}
+	for _, s := range []string{"", "Fa@x^", ":", ":F\n", ":~~@?????"} {
+		if _, err := graph.DecodeSparse6(s); err == nil {
+			t.Errorf("DecodeSparse6(%q): no error", s)
+		}
+	}
+	if g, err := graph.DecodeSparse6(":~?@c"); err != nil || g.String() != "100 []" {
+		t.Errorf("DecodeSparse6(\":~?@c\") = %v, %v; want 100 []", g, err)
+	}
+	// Errors outside a Graph6Reader aren't tied to a line.
+	if _, err := graph.DecodeGraph6("DQcc"); err == nil || err.Error() != "graph: graph6: wrong length" {
+		t.Errorf("DecodeGraph6(\"DQcc\"): error %v; want graph: graph6: wrong length", err)
+	}
+	if _, err := graph.EncodeGraph6(loop); err == nil || err.Error() != "graph: graph6: self-loop at 0" {
+		t.Errorf("EncodeGraph6(%v): error %v; want graph: graph6: self-loop at 0", loop, err)
+	}
+	for _, s := range []string{"", "DI?AO?", "&DI?AO", "&"} {
+		if _, err := graph.DecodeDigraph6(s); err == nil {
+			t.Errorf("DecodeDigraph6(%q): no error", s)
+		}
+	}
+}
+
+func TestGraph6Reader(t *testing.T) {
+	in := ">>graph6<<DQc\n\n:Fa@x^\r\n&DI?AO?\nA_"
+	r := graph.NewGraph6Reader(strings.NewReader(in))
+	var res []string
+	for {
+		g, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		res = append(res, g.String())
+	}
+	exp := []string{
+		"5 [{0 2} {0 4} {1 3} {3 4}]",
+		"7 [{0 1} {0 2} {1 2} {5 6}]",
+		"5 [(0 2) (0 4) (3 1) (3 4)]",
+		"2 [{0 1}]",
+	}
+	if strings.Join(res, ", ") != strings.Join(exp, ", ") {
+		t.Errorf("Read: %v; want %v", res, exp)
+	}
+
+	r = graph.NewGraph6Reader(strings.NewReader("A_\n&A\n"))
+	r.Read()
+	_, err := r.Read()
+	if err == nil || err.Error() != "graph: digraph6 line 2: wrong length" {
+		t.Errorf("Read: error %v; want line 2: wrong length", err)
+	}
+}
@@ -0,0 +1,489 @@
+// Package graphml reads and writes graphs in the GraphML file format.
+//
+// GraphML is an XML-based format supported by many graph tools.
+// A GraphML document declares a set of typed attributes, called keys,
+// and a graph whose nodes, edges and the graph itself may carry
+// values for these keys.
+//
+// The Read function returns a graph.Immutable together with the
+// GraphML id of each vertex and tables of typed attribute values.
+// The Write function writes any graph.Iterator, with optional vertex
+// names and attributes. In both directions, the cost of an edge is
+// mapped to an edge attribute of type long, named "weight" by default.
+//
+// Hyperedges, ports and nested graphs are not supported.
+package graphml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourbasic/graph"
+)
+
+// DefaultCostKey is the name of the edge attribute that holds the cost
+// of an edge, unless another name is given in Options.
+const DefaultCostKey = "weight"
+
+// Attrs maps attribute names to values. The values have type bool,
+// int64, float64 or string, corresponding to the GraphML types boolean,
+// int or long, float or double, and string. When writing,
+// int and int32 values are also accepted, as is float32.
+type Attrs map[string]interface{}
+
+// Key describes a GraphML attribute.
+type Key struct {
+	ID      string      // the id used in the document
+	For     string      // node, edge, graph or all
+	Name    string      // the attribute name
+	Type    string      // boolean, int, long, float, double or string
+	Default interface{} // the default value, or nil
+}
+
+// Edge is an edge in a GraphML document.
+type Edge struct {
+	ID       string // the GraphML id of the edge, may be empty
+	V, W     int    // the endpoints
+	Directed bool
+	Cost     int64
+	Attrs    Attrs
+}
+
+// Graph holds the contents of a GraphML document.
+type Graph struct {
+	// The graph, in which each undirected edge {v, w} is represented
+	// by the two edges (v, w) and (w, v).
+	*graph.Immutable
+
+	IDs         []string // the GraphML id of each vertex
+	Directed    bool     // the default direction of edges
+	Keys        []Key    // the attributes declared in the document
+	Attrs       Attrs    // the attributes of the graph
+	VertexAttrs []Attrs  // the attributes of each vertex
+	Edges       []Edge   // the edges in document order
+}
+
+// Options holds optional settings for Read and Write.
+type Options struct {
+	// CostKey is the name of the edge attribute holding the cost
+	// of an edge. If empty, DefaultCostKey is used.
+	CostKey string
+
+	// VertexID returns the GraphML id of vertex v when writing.
+	// If nil, vertex v gets id "nv", e.g. "n0", "n1", "n2",… .
+	// The ids must be distinct.
+	VertexID func(v int) string
+
+	// VertexAttrs returns the attributes of vertex v when writing.
+	// It may be nil.
+	VertexAttrs func(v int) Attrs
+}
+
+func (opts *Options) costKey() string {
+	if opts == nil || opts.CostKey == "" {
+		return DefaultCostKey
+	}
+	return opts.CostKey
+}
+
+// The XML structure of a GraphML document.
+type xmlGraphML struct {
+	XMLName xml.Name   `xml:"graphml"`
+	Keys    []xmlKey   `xml:"key"`
+	Graphs  []xmlGraph `xml:"graph"`
+}
+
+type xmlKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+type xmlGraph struct {
+	ID          string     `xml:"id,attr"`
+	EdgeDefault string     `xml:"edgedefault,attr"`
+	Data        []xmlData  `xml:"data"`
+	Nodes       []xmlNode  `xml:"node"`
+	Edges       []xmlEdge  `xml:"edge"`
+	Hyperedges  []xmlEmpty `xml:"hyperedge"`
+}
+
+type xmlNode struct {
+	ID   string    `xml:"id,attr"`
+	Data []xmlData `xml:"data"`
+}
+
+type xmlEdge struct {
+	ID       string    `xml:"id,attr"`
+	Source   string    `xml:"source,attr"`
+	Target   string    `xml:"target,attr"`
+	Directed string    `xml:"directed,attr"`
+	Data     []xmlData `xml:"data"`
+}
+
+type xmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlEmpty struct{}
+
+// Read reads a GraphML document containing a single graph.
+// Each vertex gets its number from the order in which the nodes
+// are declared, and edges are added in both directions unless
+// they are directed. The cost of an edge is taken from the cost
+// attribute, which must have type int or long; edges without
+// this attribute get the default value of the key, or zero.
+func Read(r io.Reader, opts *Options) (*Graph, error) {
+	var doc xmlGraphML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.New("graphml: " + err.Error())
+	}
+	if len(doc.Graphs) != 1 {
+		return nil, fmt.Errorf("graphml: found %d graphs; want 1", len(doc.Graphs))
+	}
+	xg := doc.Graphs[0]
+	if len(xg.Hyperedges) > 0 {
+		return nil, errors.New("graphml: hyperedges not supported")
+	}
+
+	res := &Graph{Directed: xg.EdgeDefault == "directed"}
+	keys := make(map[string]*Key)
+	for _, k := range doc.Keys {
+		key := Key{ID: k.ID, For: k.For, Name: k.Name, Type: k.Type}
+		if key.For == "" {
+			key.For = "all"
+		}
+		if key.Name == "" {
+			key.Name = key.ID
+		}
+		if key.Type == "" {
+			key.Type = "string"
+		}
+		switch key.Type {
+		case "boolean", "int", "long", "float", "double", "string":
+		default:
+			return nil, fmt.Errorf("graphml: key %q: unknown type %q", key.ID, key.Type)
+		}
+		if k.Default != nil {
+			val, err := parseValue(*k.Default, key.Type)
+			if err != nil {
+				return nil, err
+			}
+			key.Default = val
+		}
+		res.Keys = append(res.Keys, key)
+	}
+	for i := range res.Keys {
+		keys[res.Keys[i].ID] = &res.Keys[i]
+	}
+
+	// attrs returns the attributes of an element of the given kind.
+	attrs := func(kind string, data []xmlData) (Attrs, error) {
+		a := make(Attrs)
+		for _, k := range res.Keys {
+			if k.Default != nil && (k.For == kind || k.For == "all") {
+				a[k.Name] = k.Default
+			}
+		}
+		for _, d := range data {
+			k, ok := keys[d.Key]
+			if !ok {
+				return nil, fmt.Errorf("graphml: undeclared key %q", d.Key)
+			}
+			val, err := parseValue(d.Value, k.Type)
+			if err != nil {
+				return nil, err
+			}
+			a[k.Name] = val
+		}
+		return a, nil
+	}
+
+	var err error
+	if res.Attrs, err = attrs("graph", xg.Data); err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(xg.Nodes))
+	for v, node := range xg.Nodes {
+		if _, dup := index[node.ID]; dup {
+			return nil, fmt.Errorf("graphml: duplicate node %q", node.ID)
+		}
+		index[node.ID] = v
+		res.IDs = append(res.IDs, node.ID)
+		a, err := attrs("node", node.Data)
+		if err != nil {
+			return nil, err
+		}
+		res.VertexAttrs = append(res.VertexAttrs, a)
+	}
+
+	costKey := opts.costKey()
+	adj := make(adjacency, len(xg.Nodes))
+	for _, e := range xg.Edges {
+		v, okv := index[e.Source]
+		w, okw := index[e.Target]
+		if !okv || !okw {
+			return nil, fmt.Errorf("graphml: edge %q-%q: undeclared node", e.Source, e.Target)
+		}
+		a, err := attrs("edge", e.Data)
+		if err != nil {
+			return nil, err
+		}
+		edge := Edge{ID: e.ID, V: v, W: w, Directed: res.Directed, Attrs: a}
+		switch e.Directed {
+		case "true":
+			edge.Directed = true
+		case "false":
+			edge.Directed = false
+		}
+		if c, ok := a[costKey]; ok {
+			cost, ok := c.(int64)
+			if !ok {
+				return nil, fmt.Errorf("graphml: cost %v of edge %q-%q isn't an integer", c, e.Source, e.Target)
+			}
+			edge.Cost = cost
+		}
+		adj[v] = append(adj[v], neighbor{w, edge.Cost})
+		if !edge.Directed && v != w {
+			adj[w] = append(adj[w], neighbor{v, edge.Cost})
+		}
+		res.Edges = append(res.Edges, edge)
+	}
+	res.Immutable = graph.Sort(adj)
+	return res, nil
+}
+
+// parseValue parses a value of the given GraphML type.
+func parseValue(s, typ string) (interface{}, error) {
+	var val interface{}
+	var err error
+	t := strings.TrimSpace(s)
+	switch typ {
+	case "boolean":
+		val, err = strconv.ParseBool(strings.ToLower(t))
+	case "int", "long":
+		val, err = strconv.ParseInt(t, 10, 64)
+	case "float", "double":
+		val, err = strconv.ParseFloat(t, 64)
+	default:
+		val = s
+	}
+	if err != nil {
+		return nil, fmt.Errorf("graphml: bad %s value %q", typ, s)
+	}
+	return val, nil
+}
+
+// Write writes g as a GraphML document.
+//
+// Edges in opposite directions with the same cost are collected into
+// undirected edges, just like in graph.String. If all edges are undirected,
+// or self-loops, the graph is written with edgedefault="undirected".
+// Otherwise, the default is directed, and undirected edges have the
+// attribute directed="false". The cost of each edge with non-zero cost
+// is written as a value of the cost attribute, which has default value 0.
+// The keys of the vertex attributes are sorted by name.
+func Write(w io.Writer, g graph.Iterator, opts *Options) error {
+	if opts == nil {
+		opts = new(Options)
+	}
+	n := g.Order()
+	id := func(v int) string { return "n" + strconv.Itoa(v) }
+	if opts.VertexID != nil {
+		id = opts.VertexID
+	}
+
+	// Collect the vertex attributes and their types.
+	var vertexAttrs []Attrs
+	types := make(map[string]string)
+	if opts.VertexAttrs != nil {
+		vertexAttrs = make([]Attrs, n)
+		for v := range vertexAttrs {
+			vertexAttrs[v] = opts.VertexAttrs(v)
+			for name, val := range vertexAttrs[v] {
+				typ := typeOf(val)
+				if typ == "" {
+					return fmt.Errorf("graphml: attribute %q: unsupported type %T", name, val)
+				}
+				if t, ok := types[name]; ok && t != typ {
+					return fmt.Errorf("graphml: attribute %q has types %s and %s", name, t, typ)
+				}
+				types[name] = typ
+			}
+		}
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	keyID := make(map[string]string, len(names))
+	for i, name := range names {
+		keyID[name] = "d" + strconv.Itoa(i)
+	}
+
+	edges, directed, weighted := collect(g)
+
+	out := &writer{w: w}
+	out.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	out.printf("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	for _, name := range names {
+		out.printf("  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+			keyID[name], escape(name), types[name])
+	}
+	costID := "d" + strconv.Itoa(len(names))
+	if weighted {
+		out.printf("  <key id=\"%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"long\">\n",
+			costID, escape(opts.costKey()))
+		out.printf("    <default>0</default>\n")
+		out.printf("  </key>\n")
+	}
+	edgeDefault := "undirected"
+	if directed {
+		edgeDefault = "directed"
+	}
+	out.printf("  <graph edgedefault=\"%s\">\n", edgeDefault)
+	for v := 0; v < n; v++ {
+		if vertexAttrs == nil || len(vertexAttrs[v]) == 0 {
+			out.printf("    <node id=\"%s\"/>\n", escape(id(v)))
+			continue
+		}
+		out.printf("    <node id=\"%s\">\n", escape(id(v)))
+		for _, name := range names {
+			if val, ok := vertexAttrs[v][name]; ok {
+				out.printf("      <data key=\"%s\">%s</data>\n", keyID[name], escape(format(val)))
+			}
+		}
+		out.printf("    </node>\n")
+	}
+	for _, e := range edges {
+		dir := ""
+		if directed && !e.Directed {
+			dir = ` directed="false"`
+		}
+		if e.Cost == 0 {
+			out.printf("    <edge source=\"%s\" target=\"%s\"%s/>\n", escape(id(e.V)), escape(id(e.W)), dir)
+			continue
+		}
+		out.printf("    <edge source=\"%s\" target=\"%s\"%s>\n", escape(id(e.V)), escape(id(e.W)), dir)
+		out.printf("      <data key=\"%s\">%d</data>\n", costID, e.Cost)
+		out.printf("    </edge>\n")
+	}
+	out.printf("  </graph>\n")
+	out.printf("</graphml>\n")
+	return out.err
+}
+
+// collect returns the edges of g, collecting edges in opposite directions
+// with the same cost into undirected edges. It also tells if there are
+// directed edges other than self-loops, and edges with non-zero cost.
+func collect(g graph.Iterator) (edges []Edge, directed, weighted bool) {
+	type edge struct {
+		v, w int
+		c    int64
+	}
+	h := graph.Sort(g)
+	count := make(map[edge]int)
+	for v := 0; v < h.Order(); v++ {
+		h.Visit(v, func(w int, c int64) (skip bool) {
+			count[edge{v, w, c}]++
+			weighted = weighted || c != 0
+			return
+		})
+	}
+	for v := 0; v < h.Order(); v++ {
+		h.Visit(v, func(w int, c int64) (skip bool) {
+			e := edge{v, w, c}
+			if count[e] == 0 {
+				return
+			}
+			count[e]--
+			back := edge{w, v, c}
+			if v != w && count[back] > 0 {
+				count[back]--
+				edges = append(edges, Edge{V: v, W: w, Cost: c})
+				return
+			}
+			edges = append(edges, Edge{V: v, W: w, Directed: true, Cost: c})
+			directed = directed || v != w
+			return
+		})
+	}
+	return
+}
+
+// typeOf returns the GraphML type of a value, or "" if not supported.
+func typeOf(val interface{}) string {
+	switch val.(type) {
+	case bool:
+		return "boolean"
+	case int32:
+		return "int"
+	case int, int64:
+		return "long"
+	case float32:
+		return "float"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	}
+	return ""
+}
+
+func format(val interface{}) string {
+	switch val := val.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	}
+	return fmt.Sprint(val)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// writer remembers the first error.
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (w *writer) printf(format string, a ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, a...)
+	}
+}
+
+// adjacency is a multigraph represented by neighbor lists.
+type adjacency [][]neighbor
+
+type neighbor struct {
+	vertex int
+	cost   int64
+}
+
+func (g adjacency) Order() int {
+	return len(g)
+}
+
+func (g adjacency) Visit(v int, do func(w int, c int64) (skip bool)) (aborted bool) {
+	for _, e := range g[v] {
+		if do(e.vertex, e.cost) {
+			return true
+		}
+	}
+	return
+}
@@ -0,0 +1,232 @@
+package graphml
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yourbasic/graph"
+	"github.com/yourbasic/graph/build"
+)
+
+func diff(res, exp interface{}) (message string, diff bool) {
+	if !reflect.DeepEqual(res, exp) {
+		message = fmt.Sprintf("%v; want %v", res, exp)
+		diff = true
+	}
+	return
+}
+
+const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string">
+    <default>yellow</default>
+  </key>
+  <key id="d1" for="edge" attr.name="weight" attr.type="long"/>
+  <key id="d2" for="node" attr.name="big" attr.type="boolean"/>
+  <key id="d3" for="graph" attr.name="scale" attr.type="double"/>
+  <graph id="G" edgedefault="undirected">
+    <data key="d3">0.5</data>
+    <node id="a">
+      <data key="d0">green</data>
+    </node>
+    <node id="b">
+      <data key="d2">true</data>
+    </node>
+    <node id="c"/>
+    <edge id="e0" source="a" target="b">
+      <data key="d1">3</data>
+    </edge>
+    <edge source="b" target="c" directed="true"/>
+    <edge source="c" target="c"/>
+  </graph>
+</graphml>
+`
+
+func TestRead(t *testing.T) {
+	g, err := Read(strings.NewReader(doc), nil)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if mess, diff := diff(graph.String(g), "3 [{0 1}:3 (1 2) (2 2)]"); diff {
+		t.Errorf("Read: %s", mess)
+	}
+	if mess, diff := diff(g.IDs, []string{"a", "b", "c"}); diff {
+		t.Errorf("IDs: %s", mess)
+	}
+	if g.Directed {
+		t.Errorf("Directed: true; want false")
+	}
+	if mess, diff := diff(g.Attrs, Attrs{"scale": 0.5}); diff {
+		t.Errorf("Attrs: %s", mess)
+	}
+	exp := []Attrs{
+		{"color": "green"},
+		{"color": "yellow", "big": true},
+		{"color": "yellow"},
+	}
+	if mess, diff := diff(g.VertexAttrs, exp); diff {
+		t.Errorf("VertexAttrs: %s", mess)
+	}
+	edges := []Edge{
+		{ID: "e0", V: 0, W: 1, Cost: 3, Attrs: Attrs{"weight": int64(3)}},
+		{V: 1, W: 2, Directed: true, Attrs: Attrs{}},
+		{V: 2, W: 2, Attrs: Attrs{}},
+	}
+	if mess, diff := diff(g.Edges, edges); diff {
+		t.Errorf("Edges: %s", mess)
+	}
+	if mess, diff := diff(len(g.Keys), 4); diff {
+		t.Errorf("Keys: %s", mess)
+	}
+	if mess, diff := diff(g.Keys[0], Key{"d0", "node", "color", "string", "yellow"}); diff {
+		t.Errorf("Keys: %s", mess)
+	}
+
+	g, err = Read(strings.NewReader(doc), &Options{CostKey: "none"})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if mess, diff := diff(graph.String(g), "3 [{0 1} (1 2) (2 2)]"); diff {
+		t.Errorf("Read with CostKey: %s", mess)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`<graphml></graphml>`,
+		`<graphml><graph/><graph/></graphml>`,
+		`<graphml><graph><node id="a"/><node id="a"/></graph></graphml>`,
+		`<graphml><graph><node id="a"/><edge source="a" target="b"/></graph></graphml>`,
+		`<graphml><graph><node id="a"><data key="x">1</data></node></graph></graphml>`,
+		`<graphml><key id="x" attr.type="int"/><graph><node id="a"><data key="x">one</data></node></graph></graphml>`,
+		`<graphml><key id="x" attr.type="complex"/><graph/></graphml>`,
+		`<graphml><key id="weight" attr.type="double"/><graph><node id="a"/>
+			<edge source="a" target="a"><data key="weight">1.5</data></edge></graph></graphml>`,
+		`<graphml><graph><node id="a"/><hyperedge><endpoint node="a"/></hyperedge></graph></graphml>`,
+	} {
+		if _, err := Read(strings.NewReader(s), nil); err == nil {
+			t.Errorf("Read(%q): no error", s)
+		} else if !strings.HasPrefix(err.Error(), "graphml: ") {
+			t.Errorf("Read(%q): error %q lacks prefix", s, err)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := graph.New(3)
+	g.AddBoth(0, 1)
+	var buf bytes.Buffer
+	if err := Write(&buf, g, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	exp := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <graph edgedefault="undirected">
+    <node id="n0"/>
+    <node id="n1"/>
+    <node id="n2"/>
+    <edge source="n0" target="n1"/>
+  </graph>
+</graphml>
+`
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("Write: %s", mess)
+	}
+
+	g.AddCost(1, 2, 7)
+	buf.Reset()
+	err := Write(&buf, g, &Options{
+		CostKey:  "length",
+		VertexID: func(v int) string { return "v<" + strconv.Itoa(v) + ">" },
+		VertexAttrs: func(v int) Attrs {
+			if v == 1 {
+				return Attrs{"label": "b & c", "rank": 2}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	exp = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="node" attr.name="rank" attr.type="long"/>
+  <key id="d2" for="edge" attr.name="length" attr.type="long">
+    <default>0</default>
+  </key>
+  <graph edgedefault="directed">
+    <node id="v&lt;0&gt;"/>
+    <node id="v&lt;1&gt;">
+      <data key="d0">b &amp; c</data>
+      <data key="d1">2</data>
+    </node>
+    <node id="v&lt;2&gt;"/>
+    <edge source="v&lt;0&gt;" target="v&lt;1&gt;" directed="false"/>
+    <edge source="v&lt;1&gt;" target="v&lt;2&gt;">
+      <data key="d2">7</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("Write: %s", mess)
+	}
+
+	err = Write(&buf, g, &Options{
+		VertexAttrs: func(v int) Attrs { return Attrs{"x": v == 0} },
+	})
+	if err != nil {
+		t.Errorf("Write: %v", err)
+	}
+	err = Write(&buf, g, &Options{
+		VertexAttrs: func(v int) Attrs {
+			if v == 0 {
+				return Attrs{"x": 1}
+			}
+			return Attrs{"x": "one"}
+		},
+	})
+	if err == nil {
+		t.Errorf("Write with conflicting types: no error")
+	}
+	err = Write(&buf, g, &Options{
+		VertexAttrs: func(v int) Attrs { return Attrs{"x": []int{v}} },
+	})
+	if err == nil {
+		t.Errorf("Write with unsupported type: no error")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	m := graph.New(4)
+	m.AddCost(0, 1, 5)
+	m.AddCost(0, 1, 5)
+	m.AddBothCost(1, 2, -2)
+	m.AddCost(2, 1, 4)
+	m.Add(3, 3)
+	for _, g := range []graph.Iterator{
+		graph.New(0),
+		m,
+		build.Kn(5),
+		build.Grid(3, 4).AddCost(2),
+		build.Kmn(2, 3).Add(build.DirectedEdge(0, 1)),
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, g, nil); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		res, err := Read(&buf, nil)
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		if mess, diff := diff(graph.String(res), graph.String(g)); diff {
+			t.Errorf("round trip: %s", mess)
+		}
+	}
+}
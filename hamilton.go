@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// maxExact is the largest number of vertices accepted by the
+// exponential-time algorithms for Hamiltonian cycles and paths and for TSP.
+// The dynamic programming tables of TSP need about 3 GB for n = 25.
+const maxExact = 25
+
+// HamiltonianCycle returns a directed cycle in g that visits every vertex
+// exactly once. The cycle starts at vertex 0, and the closing edge
+// back to 0 is implied. If no such cycle exists, it returns an empty
+// slice and sets ok to false. Self-loops are disregarded.
+//
+// The time complexity is O(2ⁿ⋅n), where n is the number of vertices
+// in the graph. The function panics if n > 25.
+func HamiltonianCycle(g Iterator) (cycle []int, ok bool) {
+	return hamilton(g, true)
+}
+
+// HamiltonianPath returns a directed path in g that visits every vertex
+// exactly once. If no such path exists, it returns an empty slice
+// and sets ok to false. Self-loops are disregarded.
+//
+// The time complexity is O(2ⁿ⋅n), where n is the number of vertices
+// in the graph. The function panics if n > 25.
+func HamiltonianPath(g Iterator) (path []int, ok bool) {
+	return hamilton(g, false)
+}
+
+func hamilton(g Iterator, cycle bool) (path []int, ok bool) {
+	n := g.Order()
+	switch {
+	case n == 0:
+		return []int{}, false
+	case n > maxExact:
+		panic("too many vertices for exact search: n=" + strconv.Itoa(n))
+	}
+	// pred[w] is the set of vertices v ≠ w with an edge from v to w.
+	pred := make([]uint64, n)
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			if v != w {
+				pred[w] |= 1 << uint(v)
+			}
+			return
+		})
+	}
+
+	// end[S] is the set of vertices v ∊ S for which there is a path
+	// that visits exactly the vertices in S and ends at v.
+	all := uint64(1)<<uint(n) - 1
+	end := make([]uint64, all+1)
+	if cycle {
+		end[1] = 1 // Each cycle starts at 0.
+	} else {
+		for v := 0; v < n; v++ {
+			end[1<<uint(v)] = 1 << uint(v)
+		}
+	}
+	for S := uint64(1); S < all; S++ {
+		if end[S] == 0 {
+			continue
+		}
+		for rest := all &^ S; rest != 0; rest &= rest - 1 {
+			w := bits.TrailingZeros64(rest)
+			if end[S]&pred[w] != 0 {
+				end[S|1<<uint(w)] |= 1 << uint(w)
+			}
+		}
+	}
+
+	last := end[all]
+	if cycle && n > 1 {
+		last &= pred[0]
+	}
+	if last == 0 {
+		return []int{}, false
+	}
+
+	// Walk backwards from the last vertex.
+	path = make([]int, n)
+	v := bits.TrailingZeros64(last)
+	for S, i := all, n-1; ; i-- {
+		path[i] = v
+		S &^= 1 << uint(v)
+		if S == 0 {
+			break
+		}
+		v = bits.TrailingZeros64(end[S] & pred[v])
+	}
+	return path, true
+}
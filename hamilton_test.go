@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestHamiltonianCycle(t *testing.T) {
+	g := New(0)
+	cycle, ok := HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+
+	g = New(1)
+	cycle, ok = HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{0}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+
+	g = New(2)
+	g.Add(0, 1)
+	cycle, ok = HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+
+	g.Add(1, 0)
+	cycle, ok = HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{0, 1}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+
+	g = New(4)
+	g.Add(0, 2)
+	g.Add(2, 1)
+	g.Add(1, 3)
+	g.Add(3, 0)
+	g.Add(0, 1)
+	g.Add(2, 2)
+	cycle, ok = HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{0, 2, 1, 3}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+
+	// The Petersen graph has no Hamiltonian cycle.
+	g = New(10)
+	for i := 0; i < 5; i++ {
+		g.AddBoth(i, (i+1)%5)
+		g.AddBoth(i, i+5)
+		g.AddBoth(i+5, (i+2)%5+5)
+	}
+	cycle, ok = HamiltonianCycle(g)
+	if mess, diff := diff(cycle, []int{}); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("HamiltonianCycle: %s", mess)
+	}
+	path, ok := HamiltonianPath(g)
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+	checkPath("HamiltonianPath", t, g, path, false)
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	g := New(0)
+	path, ok := HamiltonianPath(g)
+	if mess, diff := diff(path, []int{}); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+
+	g = New(1)
+	path, ok = HamiltonianPath(g)
+	if mess, diff := diff(path, []int{0}); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+
+	g = New(4)
+	g.Add(3, 1)
+	g.Add(1, 0)
+	g.Add(0, 2)
+	path, ok = HamiltonianPath(g)
+	if mess, diff := diff(path, []int{3, 1, 0, 2}); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+
+	g.Delete(1, 0)
+	g.Add(1, 2)
+	path, ok = HamiltonianPath(g)
+	if mess, diff := diff(path, []int{}); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("HamiltonianPath: %s", mess)
+	}
+}
+
+// Check that path visits all vertices of g exactly once,
+// following the edges of g.
+func checkPath(mess string, t *testing.T, g Iterator, path []int, cycle bool) {
+	n := g.Order()
+	if len(path) != n {
+		t.Errorf("%s: %v has length %d; want %d", mess, path, len(path), n)
+		return
+	}
+	seen := make([]bool, n)
+	for i, v := range path {
+		if seen[v] {
+			t.Errorf("%s: %v visits %d twice", mess, path, v)
+		}
+		seen[v] = true
+		if i == n-1 && !cycle {
+			break
+		}
+		w := path[(i+1)%n]
+		if !g.Visit(v, func(u int, _ int64) bool { return u == w }) {
+			t.Errorf("%s: %v uses missing edge (%d %d)", mess, path, v, w)
+		}
+	}
+}
+
+func TestHamiltonLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("HamiltonianCycle: no panic for n=%d", maxExact+1)
+		}
+	}()
+	HamiltonianCycle(New(maxExact + 1))
+}
@@ -10,10 +10,18 @@ import (
 // with its adjacent vertices. This makes for fast and predictable
 // iteration: the Visit method produces its elements by reading
 // from a fixed sorted precomputed list. This type supports multigraphs.
+//
+// The lists are stored back to back in a single array, using four bytes
+// for each edge; the edge costs are stored in a second array only if
+// some edge has non-zero cost. The number of vertices is limited to 2³¹.
 type Immutable struct {
-	// edges[v] is a sorted list of v's neighbors.
-	edges [][]neighbor
-	stats Stats
+	// The neighbors of v are vertex[offset[v]:offset[v+1]], sorted by
+	// vertex number and then by cost. The corresponding costs are stored
+	// in cost, which is nil if all costs are zero.
+	offset []int
+	vertex []int32
+	cost   []int64
+	stats  Stats
 }
 
 type neighbor struct {
@@ -21,6 +29,9 @@ type neighbor struct {
 	cost   int64
 }
 
+// maxImmutableOrder is the maximum number of vertices in an Immutable.
+const maxImmutableOrder = 1 << 31
+
 // Sort returns an immutable copy of g with a Visit method
 // that returns its neighbors in increasing numerical order.
 func Sort(g Iterator) *Immutable {
@@ -40,49 +51,194 @@ func Transpose(g Iterator) *Immutable {
 
 func build(g Iterator, transpose bool) *Immutable {
 	n := g.Order()
-	h := &Immutable{edges: make([][]neighbor, n)}
-	for v := range h.edges {
+	checkOrder(n)
+	h := &Immutable{offset: make([]int, n+1)}
+	for v := 0; v < n; v++ {
 		g.Visit(v, func(w int, c int64) (skip bool) {
 			if w < 0 || w >= n {
 				panic("vertex out of range: " + strconv.Itoa(w))
 			}
-			if transpose {
-				h.edges[w] = append(h.edges[w], neighbor{v, c})
-			} else {
-				h.edges[v] = append(h.edges[v], neighbor{w, c})
+			h.vertex = append(h.vertex, int32(w))
+			if c != 0 && h.cost == nil {
+				h.cost = make([]int64, len(h.vertex)-1, cap(h.vertex))
+			}
+			if h.cost != nil {
+				h.cost = append(h.cost, c)
 			}
 			return
 		})
-		sort.Slice(h.edges[v], func(i, j int) bool {
-			if e := h.edges[v]; e[i].vertex == e[j].vertex {
-				return e[i].cost < e[j].cost
-			} else {
-				return e[i].vertex < e[j].vertex
+		h.offset[v+1] = len(h.vertex)
+	}
+	if transpose {
+		h = h.transpose()
+	}
+	h.sortLists()
+	h.stats = h.computeStats()
+	return h
+}
+
+// transpose returns the transpose of g, with unsorted neighbor lists.
+// It uses counting sort.
+func (g *Immutable) transpose() *Immutable {
+	n := g.Order()
+	h := &Immutable{
+		offset: make([]int, n+1),
+		vertex: make([]int32, len(g.vertex)),
+	}
+	if g.cost != nil {
+		h.cost = make([]int64, len(g.cost))
+	}
+	for _, w := range g.vertex {
+		h.offset[w+1]++
+	}
+	for v := 0; v < n; v++ {
+		h.offset[v+1] += h.offset[v]
+	}
+	next := make([]int, n)
+	copy(next, h.offset)
+	for v := 0; v < n; v++ {
+		for i := g.offset[v]; i < g.offset[v+1]; i++ {
+			w := g.vertex[i]
+			j := next[w]
+			next[w]++
+			h.vertex[j] = int32(v)
+			if g.cost != nil {
+				h.cost[j] = g.cost[i]
 			}
-		})
+		}
+	}
+	return h
+}
+
+// newImmutable returns an Immutable with the given neighbor lists,
+// which are sorted in place.
+func newImmutable(edges [][]neighbor) *Immutable {
+	n := len(edges)
+	checkOrder(n)
+	offset := make([]int, n+1)
+	weighted := false
+	for v, neighbors := range edges {
+		offset[v+1] = offset[v] + len(neighbors)
+		for _, e := range neighbors {
+			weighted = weighted || e.cost != 0
+		}
+	}
+	h := &Immutable{
+		offset: offset,
+		vertex: make([]int32, offset[n]),
 	}
-	for v, neighbors := range h.edges {
-		if len(neighbors) == 0 {
-			h.stats.Isolated++
+	if weighted {
+		h.cost = make([]int64, offset[n])
+	}
+	for v, neighbors := range edges {
+		for i, e := range neighbors {
+			h.vertex[offset[v]+i] = int32(e.vertex)
+			if weighted {
+				h.cost[offset[v]+i] = e.cost
+			}
+		}
+	}
+	h.sortLists()
+	h.stats = h.computeStats()
+	return h
+}
+
+func checkOrder(n int) {
+	if int64(n) > maxImmutableOrder {
+		panic("too many vertices: " + strconv.Itoa(n))
+	}
+}
+
+// sortLists sorts each neighbor list by vertex and then by cost.
+func (g *Immutable) sortLists() {
+	for v := 0; v < g.Order(); v++ {
+		list := neighborList{g, g.offset[v], g.offset[v+1] - g.offset[v]}
+		if !sort.IsSorted(list) {
+			sort.Sort(list)
+		}
+	}
+}
+
+// neighborList implements sort.Interface for the neighbors of a vertex.
+type neighborList struct {
+	g          *Immutable
+	start, len int
+}
+
+func (l neighborList) Len() int {
+	return l.len
+}
+
+func (l neighborList) Less(i, j int) bool {
+	i, j = l.start+i, l.start+j
+	v := l.g.vertex
+	if v[i] == v[j] {
+		return l.g.cost != nil && l.g.cost[i] < l.g.cost[j]
+	}
+	return v[i] < v[j]
+}
+
+func (l neighborList) Swap(i, j int) {
+	i, j = l.start+i, l.start+j
+	v := l.g.vertex
+	v[i], v[j] = v[j], v[i]
+	if c := l.g.cost; c != nil {
+		c[i], c[j] = c[j], c[i]
+	}
+}
+
+// computeStats computes the statistics of g from its sorted lists.
+func (g *Immutable) computeStats() (s Stats) {
+	for v := 0; v < g.Order(); v++ {
+		start, end := g.offset[v], g.offset[v+1]
+		if start == end {
+			s.Isolated++
 		}
 		prev := -1
-		for _, e := range neighbors {
-			w, c := e.vertex, e.cost
+		for i := start; i < end; i++ {
+			w := int(g.vertex[i])
 			if v == w {
-				h.stats.Loops++
+				s.Loops++
 			}
-			if c != 0 {
-				h.stats.Weighted++
+			if g.cost != nil && g.cost[i] != 0 {
+				s.Weighted++
 			}
 			if w == prev {
-				h.stats.Multi++
+				s.Multi++
 			} else {
-				h.stats.Size++
+				s.Size++
 				prev = w
 			}
 		}
 	}
-	return h
+	return
+}
+
+// neighbors returns the neighbors of v and their costs;
+// the costs are nil if all edges in g have zero cost.
+func (g *Immutable) neighbors(v int) ([]int32, []int64) {
+	start, end := g.offset[v], g.offset[v+1]
+	if g.cost == nil {
+		return g.vertex[start:end], nil
+	}
+	return g.vertex[start:end], g.cost[start:end]
+}
+
+// lists returns the neighbor lists of g.
+func (g *Immutable) lists() [][]neighbor {
+	res := make([][]neighbor, g.Order())
+	for v := range res {
+		vertex, cost := g.neighbors(v)
+		list := make([]neighbor, len(vertex))
+		for i, w := range vertex {
+			list[i].vertex = int(w)
+			if cost != nil {
+				list[i].cost = cost[i]
+			}
+		}
+		res[v] = list
+	}
+	return res
 }
 
 // Visit calls the do function for each neighbor w of v,
@@ -91,8 +247,17 @@ func build(g Iterator, transpose bool) *Immutable {
 // If do returns true, Visit returns immediately,
 // skipping any remaining neighbors, and returns true.
 func (g *Immutable) Visit(v int, do func(w int, c int64) bool) bool {
-	for _, e := range g.edges[v] {
-		if do(e.vertex, e.cost) {
+	start, end := g.offset[v], g.offset[v+1]
+	if g.cost == nil {
+		for _, w := range g.vertex[start:end] {
+			if do(int(w), 0) {
+				return true
+			}
+		}
+		return false
+	}
+	for i := start; i < end; i++ {
+		if do(int(g.vertex[i]), g.cost[i]) {
 			return true
 		}
 	}
@@ -105,18 +270,28 @@ func (g *Immutable) Visit(v int, do func(w int, c int64) bool) bool {
 // If do returns true, VisitFrom returns immediately,
 // skipping any remaining neighbors, and returns true.
 func (g *Immutable) VisitFrom(v int, a int, do func(w int, c int64) bool) bool {
-	neighbors := g.edges[v]
-	n := len(neighbors)
-	i := sort.Search(n, func(i int) bool { return a <= neighbors[i].vertex })
-	for ; i < n; i++ {
-		e := neighbors[i]
-		if do(e.vertex, e.cost) {
+	end := g.offset[v+1]
+	for i := g.search(v, a); i < end; i++ {
+		var c int64
+		if g.cost != nil {
+			c = g.cost[i]
+		}
+		if do(int(g.vertex[i]), c) {
 			return true
 		}
 	}
 	return false
 }
 
+// search returns the index of the first neighbor w of v for which w ≥ a.
+func (g *Immutable) search(v, a int) int {
+	start, end := g.offset[v], g.offset[v+1]
+	vertex := g.vertex
+	return start + sort.Search(end-start, func(i int) bool {
+		return a <= int(vertex[start+i])
+	})
+}
+
 // String returns a string representation of the graph.
 func (g *Immutable) String() string {
 	return String(g)
@@ -124,21 +299,22 @@ func (g *Immutable) String() string {
 
 // Order returns the number of vertices in the graph.
 func (g *Immutable) Order() int {
-	return len(g.edges)
+	if g.offset == nil {
+		return 0
+	}
+	return len(g.offset) - 1
 }
 
 // Edge tells if there is an edge from v to w.
 func (g *Immutable) Edge(v, w int) bool {
-	if v < 0 || v >= len(g.edges) {
+	if v < 0 || v >= g.Order() {
 		return false
 	}
-	edges := g.edges[v]
-	n := len(edges)
-	i := sort.Search(n, func(i int) bool { return w <= edges[i].vertex })
-	return i < n && w == edges[i].vertex
+	i := g.search(v, w)
+	return i < g.offset[v+1] && w == int(g.vertex[i])
 }
 
 // Degree returns the number of outward directed edges from v.
 func (g *Immutable) Degree(v int) int {
-	return len(g.edges[v])
+	return g.offset[v+1] - g.offset[v]
 }
@@ -197,3 +197,34 @@ func TestDegreeImm(t *testing.T) {
 		t.Errorf("g5c.Degree(1) %s", mess)
 	}
 }
+
+func TestCompactImm(t *testing.T) {
+	_, g1, g1c, g5, _ := SetUpImm()
+	if g1.cost != nil || g5.cost != nil {
+		t.Errorf("Sort: costs stored for unweighted graph")
+	}
+	if g1c.cost == nil {
+		t.Errorf("Sort: costs missing for weighted graph")
+	}
+
+	g := New(4)
+	g.AddCost(0, 3, 2)
+	g.Add(1, 3)
+	g.AddCost(2, 0, 5)
+	g.Add(3, 3)
+	res := Transpose(g)
+	exp := "4 [(0 2):5 (3 0):2 (3 1) (3 3)]"
+	if mess, diff := diff(res.String(), exp); diff {
+		t.Errorf("Transpose: %s", mess)
+	}
+	Consistent("Transpose compact", t, res)
+
+	var visited []int
+	res.VisitFrom(3, 1, func(w int, c int64) (skip bool) {
+		visited = append(visited, w)
+		return
+	})
+	if mess, diff := diff(visited, []int{1, 3}); diff {
+		t.Errorf("VisitFrom: %s", mess)
+	}
+}
@@ -0,0 +1,207 @@
+package graph
+
+import (
+	"sort"
+)
+
+// MaxIndependentSet returns a maximum independent set of g:
+// a largest set of vertices, no two of which are adjacent.
+// The vertices are listed in increasing order.
+//
+// Edges are treated as undirected. A vertex with a self-loop
+// is adjacent to itself and never belongs to an independent set.
+//
+// The implementation uses a branch-and-reduce algorithm:
+// vertices of degree 0 and 1 are always included, and the search
+// branches on a vertex of maximum degree, either excluding it
+// or including it and excluding all of its neighbors.
+// The time complexity is exponential and the function is intended
+// for small graphs.
+func MaxIndependentSet(g Iterator) (set []int) {
+	n := g.Order()
+	s := &misSearch{
+		adj:    undirected(g),
+		alive:  make([]bool, n),
+		degree: make([]int, n),
+		best:   []int{},
+	}
+	for v := range s.alive {
+		s.alive[v] = true
+		s.degree[v] = len(s.adj[v])
+		s.left++
+	}
+	for v := range s.alive {
+		if g.Visit(v, func(w int, _ int64) bool { return v == w }) {
+			s.remove(v)
+		}
+	}
+	s.search()
+	sort.Ints(s.best)
+	return s.best
+}
+
+// MinVertexCover returns a minimum vertex cover of g:
+// a smallest set of vertices that includes at least one endpoint
+// of every edge. The vertices are listed in increasing order.
+//
+// The minimum vertex cover is the complement of a maximum independent set,
+// and it's computed by MaxIndependentSet. The time complexity is exponential
+// and the function is intended for small graphs.
+// For large graphs, use VertexCoverApprox.
+func MinVertexCover(g Iterator) (cover []int) {
+	return complement(g.Order(), MaxIndependentSet(g))
+}
+
+// VertexCoverApprox returns a vertex cover of g that is at most
+// twice as large as a minimum vertex cover.
+// The vertices are listed in increasing order.
+//
+// The cover consists of both endpoints of the edges in a greedily
+// computed maximal matching. The time complexity is O(|E| + |V|),
+// where |E| is the number of edges and |V| the number of vertices.
+func VertexCoverApprox(g Iterator) (cover []int) {
+	n := g.Order()
+	matched := make([]bool, n)
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			if !matched[v] && !matched[w] {
+				matched[v], matched[w] = true, true
+				return true
+			}
+			return
+		})
+	}
+	cover = []int{}
+	for v, ok := range matched {
+		if ok {
+			cover = append(cover, v)
+		}
+	}
+	return
+}
+
+// IsIndependentSet tells if no two vertices in set are adjacent in g.
+// Edges are treated as undirected and a vertex with a self-loop
+// is adjacent to itself.
+func IsIndependentSet(g Iterator, set []int) bool {
+	in := make([]bool, g.Order())
+	for _, v := range set {
+		in[v] = true
+	}
+	for _, v := range set {
+		if g.Visit(v, func(w int, _ int64) bool { return in[w] }) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsVertexCover tells if every edge of g has at least one endpoint in set.
+func IsVertexCover(g Iterator, set []int) bool {
+	n := g.Order()
+	in := make([]bool, n)
+	for _, v := range set {
+		in[v] = true
+	}
+	for v := 0; v < n; v++ {
+		if !in[v] && g.Visit(v, func(w int, _ int64) bool { return !in[w] }) {
+			return false
+		}
+	}
+	return true
+}
+
+// complement returns the vertices 0..n-1 not in the sorted list set.
+func complement(n int, set []int) []int {
+	res := make([]int, 0, n-len(set))
+	for v, i := 0, 0; v < n; v++ {
+		if i < len(set) && set[i] == v {
+			i++
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// misSearch holds the state of a maximum independent set search.
+type misSearch struct {
+	adj    [][]int // sorted neighbors in the underlying undirected graph
+	alive  []bool  // vertices not yet included or excluded
+	degree []int   // degree in the subgraph induced by the alive vertices
+	left   int     // number of alive vertices
+	trail  []int   // removed vertices, in order
+	set    []int   // current independent set
+	best   []int   // largest independent set found so far
+}
+
+// remove removes v from the graph.
+func (s *misSearch) remove(v int) {
+	s.alive[v] = false
+	s.left--
+	s.trail = append(s.trail, v)
+	for _, w := range s.adj[v] {
+		s.degree[w]--
+	}
+}
+
+// take adds v to the independent set and removes v and its neighbors.
+func (s *misSearch) take(v int) {
+	s.set = append(s.set, v)
+	s.remove(v)
+	for _, w := range s.adj[v] {
+		if s.alive[w] {
+			s.remove(w)
+		}
+	}
+}
+
+// undo restores the graph and the independent set to a previous state.
+func (s *misSearch) undo(trail, set int) {
+	for len(s.trail) > trail {
+		v := s.trail[len(s.trail)-1]
+		s.trail = s.trail[:len(s.trail)-1]
+		s.alive[v] = true
+		s.left++
+		for _, w := range s.adj[v] {
+			s.degree[w]++
+		}
+	}
+	s.set = s.set[:set]
+}
+
+func (s *misSearch) search() {
+	trail, set := len(s.trail), len(s.set)
+	defer s.undo(trail, set)
+
+	// Vertices of degree 0 and 1 belong to some maximum independent set.
+	for reduced := true; reduced; {
+		reduced = false
+		for v, ok := range s.alive {
+			if ok && s.degree[v] <= 1 {
+				s.take(v)
+				reduced = true
+			}
+		}
+	}
+	if len(s.set)+s.left <= len(s.best) {
+		return
+	}
+	if s.left == 0 {
+		s.best = append(s.best[:0], s.set...)
+		return
+	}
+
+	v := -1
+	for w, ok := range s.alive {
+		if ok && (v == -1 || s.degree[w] > s.degree[v]) {
+			v = w
+		}
+	}
+	trail0, set0 := len(s.trail), len(s.set)
+	s.take(v)
+	s.search()
+	s.undo(trail0, set0)
+	s.remove(v)
+	s.search()
+}
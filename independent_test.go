@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestMaxIndependentSet(t *testing.T) {
+	g := New(0)
+	if mess, diff := diff(MaxIndependentSet(g), []int{}); diff {
+		t.Errorf("MaxIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(MinVertexCover(g), []int{}); diff {
+		t.Errorf("MinVertexCover: %s", mess)
+	}
+
+	g = New(3)
+	g.Add(1, 1)
+	g.Add(0, 2)
+	if mess, diff := diff(MaxIndependentSet(g), []int{0}); diff {
+		t.Errorf("MaxIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(MinVertexCover(g), []int{1, 2}); diff {
+		t.Errorf("MinVertexCover: %s", mess)
+	}
+
+	// The Petersen graph has independence number 4.
+	g = New(10)
+	for i := 0; i < 5; i++ {
+		g.AddBoth(i, (i+1)%5)
+		g.AddBoth(i, i+5)
+		g.AddBoth(i+5, (i+2)%5+5)
+	}
+	set := MaxIndependentSet(g)
+	if mess, diff := diff(len(set), 4); diff {
+		t.Errorf("MaxIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(IsIndependentSet(g, set), true); diff {
+		t.Errorf("IsIndependentSet: %s", mess)
+	}
+	cover := MinVertexCover(g)
+	if mess, diff := diff(len(cover), 6); diff {
+		t.Errorf("MinVertexCover: %s", mess)
+	}
+	if mess, diff := diff(IsVertexCover(g, cover), true); diff {
+		t.Errorf("IsVertexCover: %s", mess)
+	}
+
+	// Compare with brute force.
+	for i := 0; i < 30; i++ {
+		g := randomGraph(12, 20)
+		set := MaxIndependentSet(g)
+		if mess, diff := diff(len(set), bruteIndependent(g)); diff {
+			t.Errorf("MaxIndependentSet %v: %s", g, mess)
+		}
+		if mess, diff := diff(IsIndependentSet(g, set), true); diff {
+			t.Errorf("MaxIndependentSet %v: %v %s", g, set, mess)
+		}
+		cover := MinVertexCover(g)
+		if mess, diff := diff(len(cover), 12-len(set)); diff {
+			t.Errorf("MinVertexCover %v: %s", g, mess)
+		}
+		if mess, diff := diff(IsVertexCover(g, cover), true); diff {
+			t.Errorf("MinVertexCover %v: %v %s", g, cover, mess)
+		}
+		approx := VertexCoverApprox(g)
+		if mess, diff := diff(IsVertexCover(g, approx), true); diff {
+			t.Errorf("VertexCoverApprox %v: %v %s", g, approx, mess)
+		}
+		if len(approx) > 2*len(cover) {
+			t.Errorf("VertexCoverApprox %v: %v too large", g, approx)
+		}
+	}
+}
+
+func TestVertexCoverApprox(t *testing.T) {
+	g := New(0)
+	if mess, diff := diff(VertexCoverApprox(g), []int{}); diff {
+		t.Errorf("VertexCoverApprox: %s", mess)
+	}
+
+	g = New(5)
+	g.Add(0, 1)
+	g.Add(2, 1)
+	g.Add(3, 3)
+	if mess, diff := diff(VertexCoverApprox(g), []int{0, 1, 3}); diff {
+		t.Errorf("VertexCoverApprox: %s", mess)
+	}
+}
+
+func TestIsIndependentSet(t *testing.T) {
+	g := New(4)
+	g.Add(0, 1)
+	g.Add(2, 2)
+	if mess, diff := diff(IsIndependentSet(g, []int{}), true); diff {
+		t.Errorf("IsIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(IsIndependentSet(g, []int{1, 3}), true); diff {
+		t.Errorf("IsIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(IsIndependentSet(g, []int{1, 0}), false); diff {
+		t.Errorf("IsIndependentSet: %s", mess)
+	}
+	if mess, diff := diff(IsIndependentSet(g, []int{2}), false); diff {
+		t.Errorf("IsIndependentSet: %s", mess)
+	}
+
+	if mess, diff := diff(IsVertexCover(g, []int{0, 2}), true); diff {
+		t.Errorf("IsVertexCover: %s", mess)
+	}
+	if mess, diff := diff(IsVertexCover(g, []int{1}), false); diff {
+		t.Errorf("IsVertexCover: %s", mess)
+	}
+	if mess, diff := diff(IsVertexCover(g, []int{0, 1, 3}), false); diff {
+		t.Errorf("IsVertexCover: %s", mess)
+	}
+}
+
+// Check all subsets of vertices.
+func bruteIndependent(g *Mutable) (max int) {
+	n := g.Order()
+	for set := 0; set < 1<<uint(n); set++ {
+		var list []int
+		for v := 0; v < n; v++ {
+			if set&(1<<uint(v)) != 0 {
+				list = append(list, v)
+			}
+		}
+		if len(list) > max && IsIndependentSet(g, list) {
+			max = len(list)
+		}
+	}
+	return
+}
+
+func BenchmarkMaxIndependentSet(b *testing.B) {
+	b.StopTimer()
+	g := randomGraph(60, 120)
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = MaxIndependentSet(g)
+	}
+}
@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Isomorphic tells if g and h are isomorphic, disregarding edge costs.
+// If so, it returns a mapping from the vertices of g to the vertices
+// of h: there are k edges from v to w in g iff there are k edges from
+// mapping[v] to mapping[w] in h. If not, it returns an empty slice
+// and sets ok to false.
+//
+// The implementation uses the VF2 algorithm. The time complexity is
+// exponential in the worst case, but typically much better.
+func Isomorphic(g, h Iterator) (mapping []int, ok bool) {
+	return isomorphic(g, h, false)
+}
+
+// IsomorphicCost is like Isomorphic, but it also takes edge costs into
+// account: the costs of the edges from v to w in g must equal the costs
+// of the edges from mapping[v] to mapping[w] in h.
+func IsomorphicCost(g, h Iterator) (mapping []int, ok bool) {
+	return isomorphic(g, h, true)
+}
+
+func isomorphic(g, h Iterator, cost bool) (mapping []int, ok bool) {
+	if g.Order() != h.Order() {
+		return []int{}, false
+	}
+	s := newVF2(g, h, cost, false)
+	if s.pattern.size != s.target.size {
+		return []int{}, false
+	}
+	s.do = func(m []int) bool {
+		mapping, ok = append([]int{}, m...), true
+		return true
+	}
+	s.search(0)
+	if !ok {
+		return []int{}, false
+	}
+	return
+}
+
+// SubgraphIsomorphisms calls the do function for each isomorphism
+// from pattern to an induced subgraph of target, disregarding edge costs.
+// The vertex v in pattern is mapped to mapping[v] in target:
+// there are k edges from v to w in pattern iff there are k edges
+// from mapping[v] to mapping[w] in target.
+// The do function may keep the slice.
+// If do returns true, SubgraphIsomorphisms returns immediately,
+// skipping any remaining isomorphisms, and returns true.
+//
+// The implementation uses the VF2 algorithm. The time complexity is
+// exponential in the worst case, but typically much better.
+func SubgraphIsomorphisms(pattern, target Iterator, do func(mapping []int) (skip bool)) (aborted bool) {
+	return subgraphIsomorphisms(pattern, target, false, do)
+}
+
+// SubgraphIsomorphismsCost is like SubgraphIsomorphisms, but it also takes
+// edge costs into account: the costs of the edges from v to w in pattern
+// must equal the costs of the edges from mapping[v] to mapping[w] in target.
+func SubgraphIsomorphismsCost(pattern, target Iterator, do func(mapping []int) (skip bool)) (aborted bool) {
+	return subgraphIsomorphisms(pattern, target, true, do)
+}
+
+func subgraphIsomorphisms(pattern, target Iterator, cost bool, do func([]int) bool) bool {
+	if pattern.Order() > target.Order() {
+		return false
+	}
+	s := newVF2(pattern, target, cost, true)
+	s.do = func(m []int) bool {
+		return do(append([]int{}, m...))
+	}
+	return s.search(0)
+}
+
+// vf2Graph holds one of the two graphs in a VF2 search.
+type vf2Graph struct {
+	adj   [][]int            // sorted neighbors in the underlying undirected graph
+	edges map[[2]int][]int64 // sorted costs of the edges from v to w
+	out   []int              // outdegree, including duplicates
+	in    []int              // indegree, including duplicates
+	size  int                // number of edges, including duplicates
+	core  []int              // the vertex mapped to v, or -1
+	term  []int              // search depth at which v joined the terminal set, or 0
+}
+
+func newVF2Graph(g Iterator) *vf2Graph {
+	n := g.Order()
+	s := &vf2Graph{
+		adj:   undirected(g),
+		edges: make(map[[2]int][]int64),
+		out:   make([]int, n),
+		in:    make([]int, n),
+		core:  make([]int, n),
+		term:  make([]int, n),
+	}
+	for v := 0; v < n; v++ {
+		s.core[v] = -1
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			e := [2]int{v, w}
+			s.edges[e] = append(s.edges[e], c)
+			s.out[v]++
+			s.in[w]++
+			s.size++
+			return
+		})
+	}
+	for _, costs := range s.edges {
+		sort.Slice(costs, func(i, j int) bool { return costs[i] < costs[j] })
+	}
+	return s
+}
+
+// vf2 holds the state of a VF2 search for isomorphisms
+// from pattern to target.
+type vf2 struct {
+	pattern, target *vf2Graph
+	order           []int // pattern vertices in the order they are matched
+	cost            bool  // edge costs must match
+	subgraph        bool  // match an induced subgraph of target
+	do              func(mapping []int) (skip bool)
+}
+
+func newVF2(pattern, target Iterator, cost, subgraph bool) *vf2 {
+	s := &vf2{
+		pattern:  newVF2Graph(pattern),
+		target:   newVF2Graph(target),
+		cost:     cost,
+		subgraph: subgraph,
+	}
+
+	// Match the pattern in breadth-first order, starting each
+	// component at a vertex of maximum degree.
+	n := pattern.Order()
+	byDegree := make([]int, n)
+	for v := range byDegree {
+		byDegree[v] = v
+	}
+	adj := s.pattern.adj
+	sort.SliceStable(byDegree, func(i, j int) bool {
+		return len(adj[byDegree[i]]) > len(adj[byDegree[j]])
+	})
+	visited := make([]bool, n)
+	for _, v := range byDegree {
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+		for queue := []int{v}; len(queue) > 0; {
+			v := queue[0]
+			queue = queue[1:]
+			s.order = append(s.order, v)
+			for _, w := range adj[v] {
+				if !visited[w] {
+					visited[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return s
+}
+
+// search extends a partial mapping of the first depth vertices in s.order.
+func (s *vf2) search(depth int) (aborted bool) {
+	p, t := s.pattern, s.target
+	if depth == len(s.order) {
+		return s.do(p.core)
+	}
+	u := s.order[depth]
+
+	// If u is adjacent to a matched vertex v, the candidates are the
+	// neighbors of v's image; otherwise, any vertex outside the terminal set.
+	var candidates []int
+	anchor := -1
+	for _, v := range p.adj[u] {
+		if p.core[v] != -1 {
+			anchor = p.core[v]
+			break
+		}
+	}
+	if anchor == -1 {
+		for v, c := range t.core {
+			if c == -1 && t.term[v] == 0 {
+				candidates = append(candidates, v)
+			}
+		}
+	} else {
+		candidates = t.adj[anchor]
+	}
+
+	for _, v := range candidates {
+		if t.core[v] != -1 || !s.feasible(u, v) {
+			continue
+		}
+		p.match(u, v, depth+1)
+		t.match(v, u, depth+1)
+		if s.search(depth + 1) {
+			return true
+		}
+		p.unmatch(u, depth+1)
+		t.unmatch(v, depth+1)
+	}
+	return
+}
+
+// feasible tells if the pair (u, v) can be added to the current mapping.
+func (s *vf2) feasible(u, v int) bool {
+	p, t := s.pattern, s.target
+	if s.subgraph {
+		if p.out[u] > t.out[v] || p.in[u] > t.in[v] {
+			return false
+		}
+	} else if p.out[u] != t.out[v] || p.in[u] != t.in[v] {
+		return false
+	}
+	if !s.sameEdges(u, u, v, v) {
+		return false
+	}
+
+	// Edges to matched vertices must correspond.
+	// Count unmatched neighbors in and outside of the terminal sets.
+	var pTerm, pNew, tTerm, tNew int
+	for _, w := range p.adj[u] {
+		switch {
+		case p.core[w] != -1:
+			x := p.core[w]
+			if !s.sameEdges(u, w, v, x) || !s.sameEdges(w, u, x, v) {
+				return false
+			}
+		case p.term[w] != 0:
+			pTerm++
+		default:
+			pNew++
+		}
+	}
+	for _, x := range t.adj[v] {
+		switch {
+		case t.core[x] != -1:
+			w := t.core[x]
+			if !s.sameEdges(u, w, v, x) || !s.sameEdges(w, u, x, v) {
+				return false
+			}
+		case t.term[x] != 0:
+			tTerm++
+		default:
+			tNew++
+		}
+	}
+	if s.subgraph {
+		return pTerm <= tTerm && pNew <= tNew
+	}
+	return pTerm == tTerm && pNew == tNew
+}
+
+// sameEdges tells if the edges from u to w in pattern
+// correspond to the edges from v to x in target.
+func (s *vf2) sameEdges(u, w, v, x int) bool {
+	a := s.pattern.edges[[2]int{u, w}]
+	b := s.target.edges[[2]int{v, x}]
+	if len(a) != len(b) {
+		return false
+	}
+	if s.cost {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// match maps v to w and adds v and its neighbors to the terminal set.
+func (g *vf2Graph) match(v, w int, depth int) {
+	g.core[v] = w
+	if g.term[v] == 0 {
+		g.term[v] = depth
+	}
+	for _, u := range g.adj[v] {
+		if g.term[u] == 0 {
+			g.term[u] = depth
+		}
+	}
+}
+
+// unmatch undoes a call to match at the same depth.
+func (g *vf2Graph) unmatch(v int, depth int) {
+	g.core[v] = -1
+	if g.term[v] == depth {
+		g.term[v] = 0
+	}
+	for _, u := range g.adj[v] {
+		if g.term[u] == depth {
+			g.term[u] = 0
+		}
+	}
+}
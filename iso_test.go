@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIsomorphic(t *testing.T) {
+	g, h := New(0), New(0)
+	mapping, ok := Isomorphic(g, h)
+	if mess, diff := diff(mapping, []int{}); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+
+	g, h = New(1), New(2)
+	mapping, ok = Isomorphic(g, h)
+	if mess, diff := diff(mapping, []int{}); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+
+	// Same degree sequence, but not isomorphic.
+	g, h = New(6), New(6)
+	for i := 0; i < 6; i++ {
+		g.AddBoth(i, (i+1)%6)
+	}
+	for i := 0; i < 3; i++ {
+		h.AddBoth(i, (i+1)%3)
+		h.AddBoth(i+3, (i+1)%3+3)
+	}
+	mapping, ok = Isomorphic(g, h)
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+
+	// Directed edges.
+	g, h = New(3), New(3)
+	g.Add(0, 1)
+	g.Add(1, 2)
+	h.Add(2, 1)
+	h.Add(1, 0)
+	mapping, ok = Isomorphic(g, h)
+	if mess, diff := diff(mapping, []int{2, 1, 0}); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+	h.Delete(1, 0)
+	h.Add(0, 1)
+	mapping, ok = Isomorphic(g, h)
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+
+	// Costs.
+	g, h = New(3), New(3)
+	g.AddBothCost(0, 1, 1)
+	g.AddBothCost(1, 2, 2)
+	h.AddBothCost(0, 1, 2)
+	h.AddBothCost(0, 2, 1)
+	mapping, ok = IsomorphicCost(g, h)
+	if mess, diff := diff(mapping, []int{2, 0, 1}); diff {
+		t.Errorf("IsomorphicCost: %s", mess)
+	}
+	h.AddBothCost(0, 1, 3)
+	mapping, ok = IsomorphicCost(g, h)
+	if mess, diff := diff(ok, false); diff {
+		t.Errorf("IsomorphicCost: %s", mess)
+	}
+	mapping, ok = Isomorphic(g, h)
+	if mess, diff := diff(ok, true); diff {
+		t.Errorf("Isomorphic: %s", mess)
+	}
+
+	// Random permutations.
+	for i := 0; i < 50; i++ {
+		n := 1 + rand.Intn(12)
+		g := New(n)
+		for j := 0; j < 2*n; j++ {
+			g.AddCost(rand.Intn(n), rand.Intn(n), int64(rand.Intn(3)))
+		}
+		perm := rand.Perm(n)
+		h := New(n)
+		for v := 0; v < n; v++ {
+			g.Visit(v, func(w int, c int64) (skip bool) {
+				h.AddCost(perm[v], perm[w], c)
+				return
+			})
+		}
+		for _, cost := range []bool{false, true} {
+			var mapping []int
+			var ok bool
+			if cost {
+				mapping, ok = IsomorphicCost(g, Sort(h))
+			} else {
+				mapping, ok = Isomorphic(Sort(g), h)
+			}
+			if !ok {
+				t.Errorf("Isomorphic %v %v: not isomorphic", g, h)
+				continue
+			}
+			checkIsomorphism("Isomorphic", t, g, h, mapping, cost)
+		}
+	}
+}
+
+func TestSubgraphIsomorphisms(t *testing.T) {
+	count := 0
+	counter := func(mapping []int) (skip bool) {
+		count++
+		return
+	}
+
+	// Triangles in K4.
+	triangle, k4 := New(3), New(4)
+	for v := 0; v < 4; v++ {
+		for w := 0; w < 4; w++ {
+			if v != w {
+				k4.Add(v, w)
+				if v < 3 && w < 3 {
+					triangle.Add(v, w)
+				}
+			}
+		}
+	}
+	SubgraphIsomorphisms(triangle, k4, counter)
+	if mess, diff := diff(count, 24); diff {
+		t.Errorf("SubgraphIsomorphisms: %s", mess)
+	}
+
+	// Induced paths of length 2 in a 4-cycle.
+	path, c4 := New(3), New(4)
+	path.AddBoth(0, 1)
+	path.AddBoth(1, 2)
+	for i := 0; i < 4; i++ {
+		c4.AddBoth(i, (i+1)%4)
+	}
+	count = 0
+	SubgraphIsomorphisms(path, c4, counter)
+	if mess, diff := diff(count, 8); diff {
+		t.Errorf("SubgraphIsomorphisms: %s", mess)
+	}
+	count = 0
+	SubgraphIsomorphisms(path, k4, counter)
+	if mess, diff := diff(count, 0); diff {
+		t.Errorf("SubgraphIsomorphisms: %s", mess)
+	}
+
+	// Costs.
+	c4.AddBothCost(0, 1, 5)
+	path.AddBothCost(0, 1, 5)
+	count = 0
+	SubgraphIsomorphismsCost(path, c4, counter)
+	if mess, diff := diff(count, 2); diff {
+		t.Errorf("SubgraphIsomorphismsCost: %s", mess)
+	}
+
+	// Abort.
+	count = 0
+	aborted := SubgraphIsomorphisms(triangle, k4, func(mapping []int) (skip bool) {
+		count++
+		return count == 3
+	})
+	if mess, diff := diff(count, 3); diff {
+		t.Errorf("SubgraphIsomorphisms: %s", mess)
+	}
+	if mess, diff := diff(aborted, true); diff {
+		t.Errorf("SubgraphIsomorphisms: %s", mess)
+	}
+
+	// Compare with brute force.
+	for i := 0; i < 30; i++ {
+		pattern := New(1 + rand.Intn(4))
+		for j := 0; j < 3; j++ {
+			pattern.Add(rand.Intn(pattern.Order()), rand.Intn(pattern.Order()))
+		}
+		target := New(6)
+		for j := 0; j < 12; j++ {
+			target.Add(rand.Intn(6), rand.Intn(6))
+		}
+		count = 0
+		SubgraphIsomorphisms(pattern, target, func(mapping []int) (skip bool) {
+			count++
+			checkIsomorphism("SubgraphIsomorphisms", t, pattern, target, mapping, false)
+			return
+		})
+		if mess, diff := diff(count, bruteSubgraph(pattern, target)); diff {
+			t.Errorf("SubgraphIsomorphisms %v %v: %s", pattern, target, mess)
+		}
+	}
+}
+
+// Check that the edges of g correspond to the edges between
+// the image vertices in h.
+func checkIsomorphism(mess string, t *testing.T, g, h *Mutable, mapping []int, cost bool) {
+	n := g.Order()
+	for v := 0; v < n; v++ {
+		for w := 0; w < n; w++ {
+			x, y := mapping[v], mapping[w]
+			if g.Edge(v, w) != h.Edge(x, y) || cost && g.Cost(v, w) != h.Cost(x, y) {
+				t.Errorf("%s %v %v: bad mapping %v", mess, g, h, mapping)
+				return
+			}
+		}
+	}
+}
+
+// Count the injective mappings that preserve edges and non-edges.
+func bruteSubgraph(pattern, target *Mutable) (count int) {
+	n, m := pattern.Order(), target.Order()
+	mapping := make([]int, n)
+	used := make([]bool, m)
+	var try func(v int)
+	try = func(v int) {
+		if v == n {
+			count++
+			return
+		}
+	next:
+		for x := 0; x < m; x++ {
+			if used[x] {
+				continue
+			}
+			mapping[v] = x
+			for w := 0; w <= v; w++ {
+				y := mapping[w]
+				if pattern.Edge(v, w) != target.Edge(x, y) || pattern.Edge(w, v) != target.Edge(y, x) {
+					continue next
+				}
+			}
+			used[x] = true
+			try(v + 1)
+			used[x] = false
+		}
+	}
+	try(0)
+	return
+}
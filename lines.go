@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lineReader reads a line-oriented text format, one line at a time.
+type lineReader struct {
+	r      *bufio.Reader
+	format string // the name of the format, used in error messages
+	line   int    // the number of the current line
+	size   int    // the number of bytes read
+}
+
+func newLineReader(r io.Reader, format string) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, 1<<16), format: format}
+}
+
+// next returns the white-space separated fields of the next line.
+// It returns io.EOF, and no fields, at the end of input.
+func (l *lineReader) next() (fields []string, err error) {
+	s, err := l.r.ReadString('\n')
+	if err == io.EOF && s != "" {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.line++
+	l.size += len(s)
+	return strings.Fields(s), nil
+}
+
+// errorf returns an error for the current line.
+func (l *lineReader) errorf(msg string) error {
+	return &formatError{l.format, l.line, msg}
+}
+
+// vertex parses a vertex number in the range [base, n+base).
+func (l *lineReader) vertex(s string, n, base int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < base || v-base >= n {
+		return 0, l.errorf("bad vertex " + strconv.Quote(s))
+	}
+	return v - base, nil
+}
+
+// int parses a non-negative integer.
+func (l *lineReader) int(s string) (int, error) {
+	x, err := strconv.Atoi(s)
+	if err != nil || x < 0 {
+		return 0, l.errorf("bad number " + strconv.Quote(s))
+	}
+	return x, nil
+}
+
+// order parses the number of vertices of a graph.
+func (l *lineReader) order(s string) (int, error) {
+	n, err := l.int(s)
+	if err == nil && int64(n) > maxImmutableOrder {
+		return 0, l.errorf("too many vertices: " + s)
+	}
+	return n, err
+}
+
+// checkSize returns an error if a graph with n vertices is too large
+// for the input, which must have been read to the end. This bounds the
+// memory used for a graph with many isolated vertices.
+func (l *lineReader) checkSize(n int) error {
+	if n > maxIsolated && n > l.size {
+		return &formatError{l.format, noLine, "too many vertices for length"}
+	}
+	return nil
+}
+
+// cost parses an edge cost.
+func (l *lineReader) cost(s string) (int64, error) {
+	c, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, l.errorf("bad cost " + strconv.Quote(s))
+	}
+	return c, nil
+}
+
+// formatError describes a syntax error in a text format;
+// line is noLine if the error isn't tied to a line.
+type formatError struct {
+	format string
+	line   int
+	msg    string
+}
+
+const noLine = -1
+
+func (e *formatError) Error() string {
+	if e.line == noLine {
+		return "graph: " + e.format + ": " + e.msg
+	}
+	return "graph: " + e.format + " line " + strconv.Itoa(e.line) + ": " + e.msg
+}
+
+// edgeCount returns the number of edges in g.
+func edgeCount(g Iterator) (m int) {
+	if g, ok := g.(*Immutable); ok {
+		return g.stats.Size + g.stats.Multi
+	}
+	for v := 0; v < g.Order(); v++ {
+		g.Visit(v, func(int, int64) (skip bool) {
+			m++
+			return
+		})
+	}
+	return
+}
+
+// hasCost tells if g has an edge of non-zero cost.
+func hasCost(g Iterator) bool {
+	if g, ok := g.(*Immutable); ok {
+		return g.stats.Weighted > 0
+	}
+	for v := 0; v < g.Order(); v++ {
+		if g.Visit(v, func(_ int, c int64) bool { return c != 0 }) {
+			return true
+		}
+	}
+	return false
+}
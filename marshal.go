@@ -0,0 +1,276 @@
+package graph
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"strconv"
+)
+
+const (
+	binaryMagic   = "ybg"
+	binaryVersion = 1
+	binaryCosts   = 1 // flag: costs are included
+)
+
+type jsonGraph struct {
+	Order int        `json:"order"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonEdge struct {
+	V    int   `json:"v"`
+	W    int   `json:"w"`
+	Cost int64 `json:"cost,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding of g,
+// in the format described for Immutable.MarshalJSON.
+func (g *Mutable) MarshalJSON() ([]byte, error) {
+	return marshalJSON(Sort(g))
+}
+
+// UnmarshalJSON sets g to the graph described by the JSON encoding in data.
+// Duplicate edges are merged, keeping the largest cost.
+func (g *Mutable) UnmarshalJSON(data []byte) error {
+	h, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*g = *copyImmutable(h)
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of g: an object holding
+// the number of vertices and a list of edges, sorted by (v, w, cost).
+// The cost field is omitted for edges of zero cost.
+//
+//	{"order":3,"edges":[{"v":0,"w":1},{"v":1,"w":2,"cost":8}]}
+func (g *Immutable) MarshalJSON() ([]byte, error) {
+	return marshalJSON(g)
+}
+
+// UnmarshalJSON sets g to the graph described by the JSON encoding in data.
+// It should only be called on a new zero-valued graph.
+func (g *Immutable) UnmarshalJSON(data []byte) error {
+	h, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*g = *h
+	return nil
+}
+
+func marshalJSON(g *Immutable) ([]byte, error) {
+	res := jsonGraph{
+		Order: g.Order(),
+		Edges: make([]jsonEdge, 0, g.stats.Size+g.stats.Multi),
+	}
+	for v := 0; v < g.Order(); v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			res.Edges = append(res.Edges, jsonEdge{v, w, c})
+			return
+		})
+	}
+	return json.Marshal(res)
+}
+
+func unmarshalJSON(data []byte) (*Immutable, error) {
+	var g jsonGraph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, errors.New("graph: UnmarshalJSON: " + err.Error())
+	}
+	n := g.Order
+	if n < 0 {
+		return nil, errors.New("graph: UnmarshalJSON: negative order " + strconv.Itoa(n))
+	}
+	if int64(n) > maxImmutableOrder || n > maxIsolated && n > len(data) {
+		return nil, errors.New("graph: UnmarshalJSON: too many vertices: " + strconv.Itoa(n))
+	}
+	edges := make([][]neighbor, n)
+	for _, e := range g.Edges {
+		for _, v := range []int{e.V, e.W} {
+			if v < 0 || v >= n {
+				return nil, errors.New("graph: UnmarshalJSON: vertex out of range: " + strconv.Itoa(v))
+			}
+		}
+		edges[e.V] = append(edges[e.V], neighbor{e.W, e.Cost})
+	}
+	return newImmutable(edges), nil
+}
+
+// MarshalBinary returns a compact binary encoding of g,
+// in the format described for Immutable.MarshalBinary.
+func (g *Mutable) MarshalBinary() ([]byte, error) {
+	return Sort(g).MarshalBinary()
+}
+
+// UnmarshalBinary sets g to the graph described by the binary encoding in data.
+// Duplicate edges are merged, keeping the largest cost.
+func (g *Mutable) UnmarshalBinary(data []byte) error {
+	var h Immutable
+	if err := h.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	*g = *copyImmutable(&h)
+	return nil
+}
+
+// MarshalBinary returns a compact binary encoding of g,
+// which is also used by the gob package.
+//
+// The encoding starts with a header consisting of the bytes "ybg",
+// a version number, and a flags byte telling if edge costs are included;
+// they are only included if g has edges of non-zero cost.
+// The header is followed by varint-encoded fields: the number of vertices,
+// the five Stats fields returned by Check, and, for each vertex, its degree
+// and neighbors. The neighbors are delta-encoded in increasing order,
+// each followed by its cost if costs are included. The encoding ends with
+// a big-endian CRC-32 checksum (IEEE) of the preceding bytes.
+func (g *Immutable) MarshalBinary() ([]byte, error) {
+	var flags byte
+	if g.stats.Weighted > 0 {
+		flags |= binaryCosts
+	}
+	buf := append([]byte(binaryMagic), binaryVersion, flags)
+	var tmp [binary.MaxVarintLen64]byte
+	put := func(x int) {
+		buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(x))]...)
+	}
+	put(g.Order())
+	s := g.stats
+	for _, x := range []int{s.Size, s.Multi, s.Weighted, s.Loops, s.Isolated} {
+		put(x)
+	}
+	for v := 0; v < g.Order(); v++ {
+		vertex, cost := g.neighbors(v)
+		put(len(vertex))
+		prev := 0
+		for i, w := range vertex {
+			put(int(w) - prev)
+			prev = int(w)
+			if flags&binaryCosts != 0 {
+				buf = append(buf, tmp[:binary.PutVarint(tmp[:], cost[i])]...)
+			}
+		}
+	}
+	return appendChecksum(buf), nil
+}
+
+// UnmarshalBinary sets g to the graph described by the binary encoding in data.
+// It should only be called on a new zero-valued graph.
+// The statistics are read from the encoding and not recomputed.
+func (g *Immutable) UnmarshalBinary(data []byte) error {
+	d := &decoder{}
+	if len(data) < len(binaryMagic)+2+crc32.Size {
+		return d.fail("data too short")
+	}
+	body := data[:len(data)-crc32.Size]
+	if binary.BigEndian.Uint32(data[len(body):]) != crc32.ChecksumIEEE(body) {
+		return d.fail("checksum mismatch")
+	}
+	if string(body[:len(binaryMagic)]) != binaryMagic {
+		return d.fail("bad header")
+	}
+	if v := body[len(binaryMagic)]; v != binaryVersion {
+		return d.fail("unsupported version " + strconv.Itoa(int(v)))
+	}
+	flags := body[len(binaryMagic)+1]
+	if flags&^binaryCosts != 0 {
+		return d.fail("bad flags")
+	}
+	d.buf = body[len(binaryMagic)+2:]
+
+	// Each vertex and each edge takes at least one byte,
+	// which limits the size of the allocations below.
+	n := d.int(len(d.buf))
+	var s Stats
+	for _, x := range []*int{&s.Size, &s.Multi, &s.Weighted, &s.Loops, &s.Isolated} {
+		*x = d.int(-1)
+	}
+	if int64(n) > maxImmutableOrder {
+		return d.fail("too many vertices")
+	}
+	if (flags&binaryCosts != 0) != (s.Weighted > 0) {
+		return d.fail("bad flags")
+	}
+	h := Immutable{offset: make([]int, n+1), stats: s}
+	for v := 0; v < n; v++ {
+		deg := d.int(len(d.buf))
+		if d.err != nil {
+			return d.err
+		}
+		w := 0
+		for i := 0; i < deg; i++ {
+			w += d.int(n - 1 - w)
+			h.vertex = append(h.vertex, int32(w))
+			if flags&binaryCosts != 0 {
+				h.cost = append(h.cost, d.varint())
+			}
+		}
+		h.offset[v+1] = len(h.vertex)
+	}
+	if d.err == nil && len(d.buf) > 0 {
+		d.fail("trailing data")
+	}
+	if d.err != nil {
+		return d.err
+	}
+	*g = h
+	return nil
+}
+
+func appendChecksum(buf []byte) []byte {
+	var sum [crc32.Size]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(buf))
+	return append(buf, sum[:]...)
+}
+
+// decoder reads varints from buf; after the first error,
+// all methods do nothing and return zero.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) fail(msg string) error {
+	if d.err == nil {
+		d.err = errors.New("graph: UnmarshalBinary: " + msg)
+	}
+	return d.err
+}
+
+// int reads a non-negative integer no larger than max;
+// a negative max means no limit.
+func (d *decoder) int(max int) int {
+	if d.err != nil {
+		return 0
+	}
+	x, k := binary.Uvarint(d.buf)
+	if k <= 0 {
+		d.fail("bad varint")
+		return 0
+	}
+	d.buf = d.buf[k:]
+	if x > uint64(maxInt) || max >= 0 && x > uint64(max) {
+		d.fail("value out of range: " + strconv.FormatUint(x, 10))
+		return 0
+	}
+	return int(x)
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, k := binary.Varint(d.buf)
+	if k <= 0 {
+		d.fail("bad varint")
+		return 0
+	}
+	d.buf = d.buf[k:]
+	return x
+}
+
+const maxInt = int(^uint(0) >> 1)
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	for _, g := range marshalGraphs() {
+		data, _ := g.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkUnmarshalBinary(t, data)
+		if len(data) > 4 {
+			// Also try the body with a valid checksum,
+			// to get past the checksum test.
+			checkUnmarshalBinary(t, appendChecksum(data[:len(data)-4]))
+		}
+	})
+}
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	for _, g := range marshalGraphs() {
+		data, _ := json.Marshal(g)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := new(Immutable)
+		if err := json.Unmarshal(data, g); err != nil {
+			return
+		}
+			data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		h := new(Immutable)
+		if err := json.Unmarshal(data, h); err != nil {
+			t.Fatalf("UnmarshalJSON(%s): %v", data, err)
+		}
+		if mess, diff := diff(h.String(), g.String()); diff {
+			t.Errorf("JSON round trip: %s", mess)
+		}
+	})
+}
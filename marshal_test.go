@@ -0,0 +1,232 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+// marshalGraphs returns some test graphs, including a multigraph.
+func marshalGraphs() []*Immutable {
+	g := New(4)
+	g.AddBothCost(0, 1, -5)
+	g.AddCost(1, 2, Max)
+	g.AddCost(2, 2, Min)
+	g.Add(3, 0)
+	h := New(100)
+	for i := 0; i < 300; i++ {
+		h.Add(rand.Intn(100), rand.Intn(100))
+	}
+	return []*Immutable{
+		new(Immutable),
+		Sort(New(0)),
+		Sort(New(3)),
+		Sort(g),
+		Sort(h),
+		Sort(Multi{}),
+	}
+}
+
+func TestJSON(t *testing.T) {
+	g := New(3)
+	g.AddBoth(0, 1)
+	g.AddCost(1, 2, 8)
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	exp := `{"order":3,"edges":[{"v":0,"w":1},{"v":1,"w":0},{"v":1,"w":2,"cost":8}]}`
+	if mess, diff := diff(string(data), exp); diff {
+		t.Errorf("MarshalJSON: %s", mess)
+	}
+	m := new(Mutable)
+	if err := json.Unmarshal(data, m); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if mess, diff := diff(m.String(), g.String()); diff {
+		t.Errorf("UnmarshalJSON: %s", mess)
+	}
+
+	// Duplicate edges in a Mutable graph keep the largest cost.
+	data = []byte(`{"order":2,"edges":[{"v":0,"w":1,"cost":5},{"v":0,"w":1,"cost":-2}]}`)
+	if err := json.Unmarshal(data, m); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if mess, diff := diff(m.Cost(0, 1), int64(5)); diff {
+		t.Errorf("UnmarshalJSON: %s", mess)
+	}
+
+	for _, g := range marshalGraphs() {
+		data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		h := new(Immutable)
+		if err := json.Unmarshal(data, h); err != nil {
+			t.Fatalf("UnmarshalJSON: %v", err)
+		}
+		if mess, diff := diff(h.String(), g.String()); diff {
+			t.Errorf("JSON round trip: %s", mess)
+		}
+		if mess, diff := diff(Check(h), Check(g)); diff {
+			t.Errorf("JSON round trip: %s", mess)
+		}
+	}
+
+	for _, s := range []string{
+		`[]`,
+		`{"order":-1}`,
+		`{"order":2,"edges":[{"v":0,"w":2}]}`,
+		`{"order":2,"edges":[{"v":-1,"w":0}]}`,
+		`{"order":2,"edges":[{"v":0,"w":1,"cost":1.5}]}`,
+		`{"order":3000000000}`,
+		`{"order":65537}`,
+	} {
+		if err := json.Unmarshal([]byte(s), new(Immutable)); err == nil {
+			t.Errorf("UnmarshalJSON(%s): no error", s)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	for _, g := range marshalGraphs() {
+		data, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		h := new(Immutable)
+		if err := h.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if mess, diff := diff(h.String(), g.String()); diff {
+			t.Errorf("binary round trip: %s", mess)
+		}
+		if mess, diff := diff(Check(h), Check(g)); diff {
+			t.Errorf("binary round trip: %s", mess)
+		}
+
+		m := Copy(g)
+		data, err = m.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		m1 := new(Mutable)
+		if err := m1.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if mess, diff := diff(m1, m); diff {
+			t.Errorf("binary round trip: %s", mess)
+		}
+	}
+
+	// Edge costs are omitted for unweighted graphs.
+	g := New(3)
+	g.Add(0, 2)
+	data, _ := g.MarshalBinary()
+	exp := []byte{'y', 'b', 'g', 1, 0, 3, 1, 0, 0, 0, 2, 1, 2, 0, 0}
+	if mess, diff := diff(data[:len(data)-4], exp); diff {
+		t.Errorf("MarshalBinary: %s", mess)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type pair struct {
+		M *Mutable
+		I *Immutable
+	}
+	g := New(5)
+	g.AddBothCost(0, 4, 3)
+	g.Add(2, 2)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(pair{g, Sort(Multi{})}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var res pair
+	if err := gob.NewDecoder(&buf).Decode(&res); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if mess, diff := diff(res.M, g); diff {
+		t.Errorf("gob: %s", mess)
+	}
+	if mess, diff := diff(res.I.String(), String(Multi{})); diff {
+		t.Errorf("gob: %s", mess)
+	}
+}
+
+// TestBinaryCorrupt checks that UnmarshalBinary rejects corrupted input
+// without panicking.
+func TestBinaryCorrupt(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, g := range marshalGraphs() {
+		data, _ := g.MarshalBinary()
+		for i := 0; i < 200; i++ {
+			bad := append([]byte(nil), data...)
+			switch r.Intn(3) {
+			case 0:
+				bad = bad[:r.Intn(len(bad))]
+			case 1:
+				bad[r.Intn(len(bad))] ^= byte(1 + r.Intn(255))
+			case 2:
+				bad = append(bad, byte(r.Intn(256)))
+			}
+			if err := new(Immutable).UnmarshalBinary(bad); err == nil {
+				t.Errorf("UnmarshalBinary(%v): no error", bad)
+			}
+
+			// With a valid checksum, the corruption may go undetected,
+			// but the result must then be a valid graph.
+			if len(bad) > crc32.Size {
+				checkUnmarshalBinary(t, appendChecksum(bad[:len(bad)-crc32.Size]))
+			}
+		}
+	}
+
+	// Malformed bodies with valid checksums.
+	for _, body := range [][]byte{
+		{'y', 'b', 'g', 2, 0, 0, 0, 0, 0, 0, 0},
+		{'x', 'b', 'g', 1, 0, 0, 0, 0, 0, 0, 0},
+		{'y', 'b', 'g', 1, 2, 0, 0, 0, 0, 0, 0},
+		{'y', 'b', 'g', 1, 0, 200, 0, 0, 0, 0, 0},
+		{'y', 'b', 'g', 1, 0, 1, 0, 0, 0, 0, 0, 1, 1},
+		{'y', 'b', 'g', 1, 0, 2, 0, 0, 0, 0, 0, 2, 1, 1, 0},
+		{'y', 'b', 'g', 1, 1, 1, 0, 0, 0, 0, 0, 1, 0},
+		{'y', 'b', 'g', 1, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+		{'y', 'b', 'g', 1, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{'y', 'b', 'g', 1, 1, 1, 0, 0, 0, 0, 0, 0},
+		{'y', 'b', 'g', 1, 0, 2, 1, 0, 1, 0, 0, 1, 1, 0},
+	} {
+		if err := new(Immutable).UnmarshalBinary(appendChecksum(body)); err == nil {
+			t.Errorf("UnmarshalBinary(%v): no error", body)
+		}
+	}
+}
+
+// checkUnmarshalBinary checks that UnmarshalBinary either rejects data or
+// returns a graph that survives another round trip unchanged.
+func checkUnmarshalBinary(t *testing.T, data []byte) {
+	g := new(Immutable)
+	if err := g.UnmarshalBinary(data); err != nil {
+		return
+	}
+	// The stats are read from data; compute them to check for duplicates.
+	if g.computeStats().Multi == 0 {
+		Consistent("UnmarshalBinary", t, g)
+	}
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	h := new(Immutable)
+	if err := h.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(%v): %v", data, err)
+	}
+	if mess, diff := diff(h.String(), g.String()); diff {
+		t.Errorf("binary round trip: %s", mess)
+	}
+	if mess, diff := diff(Check(h), Check(g)); diff {
+		t.Errorf("binary round trip: %s", mess)
+	}
+}
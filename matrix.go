@@ -0,0 +1,131 @@
+package graph
+
+import "sort"
+
+// CSR is a square sparse matrix in compressed sparse row format.
+// The entries of row i are stored in Col[Row[i]:Row[i+1]], in increasing
+// column order, and in the corresponding elements of Val.
+// Entries that aren't stored are zero.
+type CSR struct {
+	N   int     // The number of rows and columns.
+	Row []int   // Row offsets; len(Row) = N+1.
+	Col []int   // Column indices.
+	Val []int64 // Values.
+}
+
+// At returns the entry in row i and column j.
+func (m *CSR) At(i, j int) int64 {
+	cols := m.Col[m.Row[i]:m.Row[i+1]]
+	k := sort.SearchInts(cols, j)
+	if k < len(cols) && cols[k] == j {
+		return m.Val[m.Row[i]+k]
+	}
+	return 0
+}
+
+// AdjacencyMatrix returns the adjacency matrix of g. The entry in row v
+// and column w is the number of edges from v to w; only edges are stored.
+//
+// For an Immutable graph the matrix is built directly from its sorted
+// neighbor arrays in time O(|E| + |V|); other graphs are first copied
+// by Sort.
+func AdjacencyMatrix(g Iterator) *CSR {
+	return toCSR(Sort(g), func(int64) int64 { return 1 })
+}
+
+// CostMatrix returns the weighted adjacency matrix of g. The entry in row v
+// and column w is the sum of the costs of the edges from v to w; an entry
+// is stored for each pair of vertices connected by an edge, even if it's zero.
+func CostMatrix(g Iterator) *CSR {
+	return toCSR(Sort(g), func(c int64) int64 { return c })
+}
+
+// DegreeMatrix returns the diagonal matrix of outdegrees of g.
+// All diagonal entries are stored.
+func DegreeMatrix(g Iterator) *CSR {
+	n := g.Order()
+	m := &CSR{
+		N:   n,
+		Row: make([]int, n+1),
+		Col: make([]int, n),
+		Val: make([]int64, n),
+	}
+	for v := 0; v < n; v++ {
+		m.Row[v+1] = v + 1
+		m.Col[v] = v
+		g.Visit(v, func(int, int64) (skip bool) {
+			m.Val[v]++
+			return
+		})
+	}
+	return m
+}
+
+// LaplacianMatrix returns the Laplacian matrix D - A of g, where D is the
+// degree matrix and A the adjacency matrix of g. All diagonal entries are
+// stored, as are the entries corresponding to edges.
+func LaplacianMatrix(g Iterator) *CSR {
+	a := AdjacencyMatrix(g)
+	n := a.N
+	m := &CSR{
+		N:   n,
+		Row: make([]int, n+1),
+		Col: make([]int, 0, len(a.Col)+n),
+		Val: make([]int64, 0, len(a.Col)+n),
+	}
+	for v := 0; v < n; v++ {
+		start, end := a.Row[v], a.Row[v+1]
+		var deg int64
+		for k := start; k < end; k++ {
+			deg += a.Val[k]
+		}
+		// The diagonal entry goes in its place in the sorted row.
+		k := start
+		for ; k < end && a.Col[k] < v; k++ {
+			m.Col = append(m.Col, a.Col[k])
+			m.Val = append(m.Val, -a.Val[k])
+		}
+		if k < end && a.Col[k] == v {
+			deg -= a.Val[k]
+			k++
+		}
+		m.Col = append(m.Col, v)
+		m.Val = append(m.Val, deg)
+		for ; k < end; k++ {
+			m.Col = append(m.Col, a.Col[k])
+			m.Val = append(m.Val, -a.Val[k])
+		}
+		m.Row[v+1] = len(m.Col)
+	}
+	return m
+}
+
+// toCSR returns a matrix with one entry for each pair of vertices
+// connected by an edge; the value of the entry is the sum of val(c)
+// over the costs c of these edges.
+func toCSR(g *Immutable, val func(c int64) int64) *CSR {
+	n := g.Order()
+	m := &CSR{
+		N:   n,
+		Row: make([]int, n+1),
+		Col: make([]int, 0, g.stats.Size),
+		Val: make([]int64, 0, g.stats.Size),
+	}
+	for v := 0; v < n; v++ {
+		vertex, cost := g.neighbors(v)
+		for i, w := range vertex {
+			var c int64
+			if cost != nil {
+				c = cost[i]
+			}
+			if i > 0 && w == vertex[i-1] {
+				m.Val[len(m.Val)-1] += val(c)
+				continue
+			}
+			m.Col = append(m.Col, int(w))
+			m.Val = append(m.Val, val(c))
+		}
+		m.Row[v+1] = len(m.Col)
+	}
+	return m
+}
@@ -0,0 +1,89 @@
+package graph
+
+import "testing"
+
+func TestMatrices(t *testing.T) {
+	g := New(4)
+	g.AddBothCost(0, 1, 5)
+	g.AddCost(1, 2, -2)
+	g.Add(2, 2)
+
+	a := AdjacencyMatrix(g)
+	exp := &CSR{
+		N:   4,
+		Row: []int{0, 1, 3, 4, 4},
+		Col: []int{1, 0, 2, 2},
+		Val: []int64{1, 1, 1, 1},
+	}
+	if mess, diff := diff(a, exp); diff {
+		t.Errorf("AdjacencyMatrix: %s", mess)
+	}
+	if a.At(1, 2) != 1 || a.At(2, 1) != 0 || a.At(3, 3) != 0 {
+		t.Errorf("At: wrong value")
+	}
+
+	exp.Val = []int64{5, 5, -2, 0}
+	if mess, diff := diff(CostMatrix(g), exp); diff {
+		t.Errorf("CostMatrix: %s", mess)
+	}
+
+	d := DegreeMatrix(g)
+	exp = &CSR{
+		N:   4,
+		Row: []int{0, 1, 2, 3, 4},
+		Col: []int{0, 1, 2, 3},
+		Val: []int64{1, 2, 1, 0},
+	}
+	if mess, diff := diff(d, exp); diff {
+		t.Errorf("DegreeMatrix: %s", mess)
+	}
+
+	l := LaplacianMatrix(g)
+	exp = &CSR{
+		N:   4,
+		Row: []int{0, 2, 5, 6, 7},
+		Col: []int{0, 1, 0, 1, 2, 2, 3},
+		Val: []int64{1, -1, -1, 2, -1, 0, 0},
+	}
+	if mess, diff := diff(l, exp); diff {
+		t.Errorf("LaplacianMatrix: %s", mess)
+	}
+
+	// Multiple edges are counted, and their costs added.
+	a = AdjacencyMatrix(Multi{})
+	exp = &CSR{
+		N:   3,
+		Row: []int{0, 2, 3, 3},
+		Col: []int{0, 1, 0},
+		Val: []int64{5, 6, 4},
+	}
+	if mess, diff := diff(a, exp); diff {
+		t.Errorf("AdjacencyMatrix: %s", mess)
+	}
+	if c := CostMatrix(Multi{}); c.At(0, 0) != 10 || c.At(0, 1) != 32 || c.At(1, 0) != 20 {
+		t.Errorf("CostMatrix: %v", c)
+	}
+
+	// An Immutable graph without costs.
+	h := New(2)
+	h.Add(0, 1)
+	h.Add(1, 1)
+	exp = &CSR{N: 2, Row: []int{0, 1, 2}, Col: []int{1, 1}, Val: []int64{0, 0}}
+	if mess, diff := diff(CostMatrix(Sort(h)), exp); diff {
+		t.Errorf("CostMatrix: %s", mess)
+	}
+
+	// The rows of a Laplacian sum to zero.
+	for _, g := range []Iterator{g, Multi{}, New(0)} {
+		l := LaplacianMatrix(g)
+		for i := 0; i < l.N; i++ {
+			var sum int64
+			for k := l.Row[i]; k < l.Row[i+1]; k++ {
+				sum += l.Val[k]
+			}
+			if sum != 0 {
+				t.Errorf("LaplacianMatrix(%v): row %d sums to %d", g, i, sum)
+			}
+		}
+	}
+}
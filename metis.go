@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadMETIS reads an undirected graph in the input format of the METIS
+// graph partitioning package. Lines starting with % are comments.
+// The first line "n m [fmt [ncon]]" gives the number of vertices
+// and undirected edges; it is followed by one line for each vertex,
+// listing its neighbors. The vertices are numbered from 1 to n in the
+// file and from 0 to n-1 in the graph. Each edge is listed at both
+// of its endpoints.
+//
+// If the last digit of fmt is 1, each neighbor is followed by the cost
+// of the edge. If the middle digit is 1, each line starts with ncon
+// vertex weights, and if the first digit is 1, it starts with a vertex
+// size. Vertex weights and sizes are skipped.
+//
+// As for ReadDIMACS, a graph with more than 65536 vertices is rejected
+// unless the input is at least as long as the number of vertices.
+func ReadMETIS(r io.Reader) (*Immutable, error) {
+	in := newLineReader(r, "METIS")
+	// next returns the next non-comment line.
+	next := func() ([]string, error) {
+		for {
+			fields, err := in.next()
+			if err != nil || len(fields) == 0 || fields[0][0] != '%' {
+				return fields, err
+			}
+		}
+	}
+
+	header, err := next()
+	if err == io.EOF || err == nil && (len(header) < 2 || len(header) > 4) {
+		return nil, in.errorf("expected n m [fmt [ncon]]")
+	}
+	if err != nil {
+		return nil, err
+	}
+	n, err := in.order(header[0])
+	if err != nil {
+		return nil, err
+	}
+	m, err := in.int(header[1])
+	if err != nil {
+		return nil, err
+	}
+	var vsize, vwgt, ewgt bool
+	if len(header) >= 3 {
+		f := header[2]
+		if len(f) > 3 || strings.Trim(f, "01") != "" {
+			return nil, in.errorf("bad fmt " + strconv.Quote(f))
+		}
+		for len(f) < 3 {
+			f = "0" + f
+		}
+		vsize, vwgt, ewgt = f[0] == '1', f[1] == '1', f[2] == '1'
+	}
+	ncon := 0
+	if vwgt {
+		ncon = 1
+	}
+	if len(header) == 4 {
+		if ncon, err = in.int(header[3]); err != nil {
+			return nil, err
+		}
+	}
+	skip := ncon
+	if vsize {
+		skip++
+	}
+
+	b := NewImmutableBuilder(n)
+	size := 0
+	for v := 0; v < n; v++ {
+		fields, err := next()
+		if err == io.EOF {
+			break // The remaining vertices have no neighbors.
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) < skip {
+			return nil, in.errorf("missing vertex weights")
+		}
+		fields = fields[skip:]
+		step := 1
+		if ewgt {
+			step = 2
+			if len(fields)%2 != 0 {
+				return nil, in.errorf("missing edge cost")
+			}
+		}
+		for i := 0; i < len(fields); i += step {
+			w, err := in.vertex(fields[i], n, 1)
+			if err != nil {
+				return nil, err
+			}
+			var c int64
+			if ewgt {
+				if c, err = in.cost(fields[i+1]); err != nil {
+					return nil, err
+				}
+			}
+			b.AddCost(v, w, c)
+			size++
+		}
+	}
+	for {
+		fields, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			return nil, in.errorf("more than " + strconv.Itoa(n) + " vertices")
+		}
+	}
+	if size != 2*m {
+		return nil, in.errorf("found " + strconv.Itoa(size) + " neighbors, expected " + strconv.Itoa(2*m))
+	}
+	if err := in.checkSize(n); err != nil {
+		return nil, err
+	}
+	return b.Finish(), nil
+}
+
+// WriteMETIS writes g in the METIS format described for ReadMETIS.
+// The graph must be undirected, with no self-loops or duplicate edges.
+// The edge costs are written only if g has edges of non-zero cost.
+func WriteMETIS(w io.Writer, g Iterator) error {
+	edges, count := countEdges(g)
+	weighted := false
+	for i, e := range edges {
+		switch {
+		case e.v == e.w:
+			return errors.New("graph: WriteMETIS: self-loop at " + strconv.Itoa(e.v))
+		case count[e] > 1 || i > 0 && edges[i-1].v == e.v && edges[i-1].w == e.w:
+			return errors.New("graph: WriteMETIS: duplicate edge (" +
+				strconv.Itoa(e.v) + " " + strconv.Itoa(e.w) + ")")
+		case count[edge{e.w, e.v, e.c}] == 0:
+			return errors.New("graph: WriteMETIS: directed edge (" +
+				strconv.Itoa(e.v) + " " + strconv.Itoa(e.w) + ")")
+		}
+		weighted = weighted || e.c != 0
+	}
+
+	out := bufio.NewWriter(w)
+	n := g.Order()
+	out.WriteString(strconv.Itoa(n) + " " + strconv.Itoa(len(edges)/2))
+	if weighted {
+		out.WriteString(" 001")
+	}
+	out.WriteString("\n")
+	// The edges are sorted by (v, w, c), so the lines can be written in order.
+	i := 0
+	for v := 0; v < n; v++ {
+		sep := ""
+		for ; i < len(edges) && edges[i].v == v; i++ {
+			e := edges[i]
+			out.WriteString(sep + strconv.Itoa(e.w+1))
+			if weighted {
+				out.WriteString(" " + strconv.FormatInt(e.c, 10))
+			}
+			sep = " "
+		}
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
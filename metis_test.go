@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadMETIS(t *testing.T) {
+	in := `% A graph with 4 vertices and 2 edges
+4 2
+2 3
+1
+1
+
+`
+	g, err := ReadMETIS(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if mess, diff := diff(g.String(), "4 [{0 1} {0 2}]"); diff {
+		t.Errorf("ReadMETIS: %s", mess)
+	}
+
+	// Vertex sizes, two vertex weights and edge costs.
+	in = `3 2 111 2
+% size, weights, neighbors and costs
+1 5 6 2 7 3 8
+1 0 0 1 7
+% comment
+1 0 0 1 8
+`
+	g, err = ReadMETIS(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [{0 1}:7 {0 2}:8]"); diff {
+		t.Errorf("ReadMETIS: %s", mess)
+	}
+
+	g, err = ReadMETIS(strings.NewReader("3 1 1\n2 -4\n1 -4\n"))
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [{0 1}:-4]"); diff {
+		t.Errorf("ReadMETIS: %s", mess)
+	}
+
+	for _, s := range []string{
+		"",
+		"3\n",
+		"3 1 2\n",
+		"3 1 0001\n",
+		"2 1\n2\n",
+		"2 1\n2\n1\n1\n",
+		"2 1\n3\n1\n",
+		"2 1 1\n2\n1 1\n",
+		"2 1 10\n2\n1\n",
+		"2 1 0 x\n2\n1\n",
+		"3000000000 0\n",
+	} {
+		if _, err := ReadMETIS(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadMETIS(%q): no error", s)
+		} else if !strings.HasPrefix(err.Error(), "graph: METIS line ") {
+			t.Errorf("ReadMETIS(%q): bad error %q", s, err)
+		}
+	}
+
+	// Many isolated vertices need a long input.
+	if g, err := ReadMETIS(strings.NewReader("65536 0\n")); err != nil || g.Order() != 65536 {
+		t.Errorf("ReadMETIS: %v", err)
+	}
+	_, err = ReadMETIS(strings.NewReader("2147483647 0\n"))
+	if mess, diff := diff(err.Error(), "graph: METIS: too many vertices for length"); diff {
+		t.Errorf("ReadMETIS: %s", mess)
+	}
+}
+
+func TestWriteMETIS(t *testing.T) {
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(0, 2)
+	var buf bytes.Buffer
+	if err := WriteMETIS(&buf, g); err != nil {
+		t.Fatalf("WriteMETIS: %v", err)
+	}
+	if mess, diff := diff(buf.String(), "4 2\n2 3\n1\n1\n\n"); diff {
+		t.Errorf("WriteMETIS: %s", mess)
+	}
+
+	g.AddBothCost(2, 3, 9)
+	buf.Reset()
+	if err := WriteMETIS(&buf, g); err != nil {
+		t.Fatalf("WriteMETIS: %v", err)
+	}
+	exp := "4 3 001\n2 0 3 0\n1 0\n1 0 4 9\n3 9\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteMETIS: %s", mess)
+	}
+	h, err := ReadMETIS(&buf)
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if mess, diff := diff(h.String(), g.String()); diff {
+		t.Errorf("METIS round trip: %s", mess)
+	}
+
+	g = New(2)
+	g.Add(0, 1)
+	if err := WriteMETIS(&buf, g); err == nil {
+		t.Errorf("WriteMETIS directed: no error")
+	}
+	g.AddCost(1, 0, 1)
+	if err := WriteMETIS(&buf, g); err == nil {
+		t.Errorf("WriteMETIS different costs: no error")
+	}
+	g = New(1)
+	g.Add(0, 0)
+	if err := WriteMETIS(&buf, g); err == nil {
+		t.Errorf("WriteMETIS loop: no error")
+	}
+	if err := WriteMETIS(&buf, Multi{}); err == nil {
+		t.Errorf("WriteMETIS multigraph: no error")
+	}
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadMatrixMarket reads a graph stored as a square sparse matrix in the
+// coordinate format of the Matrix Market exchange format. The file starts
+// with the header line
+//
+//	%%MatrixMarket matrix coordinate field symmetry
+//
+// where field is pattern or integer, and symmetry is general or symmetric.
+// The header is followed by comment lines starting with %, a size line
+// "n n k", and k entry lines "i j" or "i j c". Each entry describes
+// an edge from i to j with cost c, or zero cost for pattern matrices.
+// The rows and columns are numbered from 1 to n in the file
+// and the vertices from 0 to n-1 in the graph.
+//
+// In a symmetric matrix, only entries on or below the diagonal are given,
+// and each entry (i, j), i ≠ j, describes the two edges (i, j) and (j, i).
+// Duplicate entries give multiple edges.
+//
+// As for ReadDIMACS, a graph with more than 65536 vertices is rejected
+// unless the input is at least as long as the number of vertices.
+func ReadMatrixMarket(r io.Reader) (*Immutable, error) {
+	in := newLineReader(r, "MatrixMarket")
+	header, err := in.next()
+	if err == io.EOF || err == nil &&
+		(len(header) != 5 || !strings.EqualFold(header[0], "%%MatrixMarket")) {
+		return nil, in.errorf("expected %%MatrixMarket header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(header[i])
+	}
+	if header[1] != "matrix" || header[2] != "coordinate" {
+		return nil, in.errorf("only coordinate matrices supported")
+	}
+	pattern := header[3] == "pattern"
+	if !pattern && header[3] != "integer" {
+		return nil, in.errorf("unsupported field " + strconv.Quote(header[3]))
+	}
+	symmetric := header[4] == "symmetric"
+	if !symmetric && header[4] != "general" {
+		return nil, in.errorf("unsupported symmetry " + strconv.Quote(header[4]))
+	}
+
+	// next returns the next line that isn't a comment or empty.
+	next := func() ([]string, error) {
+		for {
+			fields, err := in.next()
+			if err != nil || len(fields) > 0 && fields[0][0] != '%' {
+				return fields, err
+			}
+		}
+	}
+	size, err := next()
+	if err == io.EOF || err == nil && len(size) != 3 {
+		return nil, in.errorf("expected size line")
+	}
+	if err != nil {
+		return nil, err
+	}
+	n, err := in.order(size[0])
+	if err != nil {
+		return nil, err
+	}
+	if m, err := in.int(size[1]); err != nil || m != n {
+		return nil, in.errorf("matrix not square")
+	}
+	k, err := in.int(size[2])
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewImmutableBuilder(n)
+	for entries := 0; ; entries++ {
+		fields, err := next()
+		if err == io.EOF {
+			if entries != k {
+				return nil, in.errorf("found " + strconv.Itoa(entries) + " entries, expected " + strconv.Itoa(k))
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pattern && len(fields) != 2 || !pattern && len(fields) != 3 {
+			return nil, in.errorf("bad entry")
+		}
+		v, err := in.vertex(fields[0], n, 1)
+		if err != nil {
+			return nil, err
+		}
+		w, err := in.vertex(fields[1], n, 1)
+		if err != nil {
+			return nil, err
+		}
+		var c int64
+		if !pattern {
+			if c, err = in.cost(fields[2]); err != nil {
+				return nil, err
+			}
+		}
+		if symmetric && v < w {
+			return nil, in.errorf("entry above diagonal in symmetric matrix")
+		}
+		if symmetric {
+			b.AddBothCost(v, w, c)
+		} else {
+			b.AddCost(v, w, c)
+		}
+	}
+	if err := in.checkSize(n); err != nil {
+		return nil, err
+	}
+	return b.Finish(), nil
+}
+
+// WriteMatrixMarket writes g as a general coordinate matrix in the
+// Matrix Market format described for ReadMatrixMarket, with one entry
+// for each edge. The matrix is written as an integer matrix if g has edges
+// of non-zero cost, and as a pattern matrix otherwise.
+func WriteMatrixMarket(w io.Writer, g Iterator) error {
+	n := g.Order()
+	weighted := hasCost(g)
+	out := bufio.NewWriter(w)
+	if weighted {
+		out.WriteString("%%MatrixMarket matrix coordinate integer general\n")
+	} else {
+		out.WriteString("%%MatrixMarket matrix coordinate pattern general\n")
+	}
+	out.WriteString(strconv.Itoa(n) + " " + strconv.Itoa(n) + " " + strconv.Itoa(edgeCount(g)) + "\n")
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			out.WriteString(strconv.Itoa(v+1) + " " + strconv.Itoa(w+1))
+			if weighted {
+				out.WriteString(" " + strconv.FormatInt(c, 10))
+			}
+			out.WriteString("\n")
+			return
+		})
+	}
+	return out.Flush()
+}
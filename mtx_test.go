@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	in := `%%MatrixMarket matrix coordinate pattern general
+% A comment
+%
+3 3 4
+1 2
+2 3
+3 3
+
+2 3
+`
+	g, err := ReadMatrixMarket(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadMatrixMarket: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [(0 1) 2×(1 2) (2 2)]"); diff {
+		t.Errorf("ReadMatrixMarket: %s", mess)
+	}
+
+	in = `%%MatrixMarket Matrix Coordinate Integer Symmetric
+3 3 3
+2 1 7
+3 1 -1
+3 3 4
+`
+	g, err = ReadMatrixMarket(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadMatrixMarket: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [{0 1}:7 {0 2}:-1 (2 2):4]"); diff {
+		t.Errorf("ReadMatrixMarket: %s", mess)
+	}
+
+	for _, s := range []string{
+		"",
+		"%%MatrixMarket matrix array integer general\n2 2\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 0\n",
+		"%%MatrixMarket matrix coordinate pattern hermitian\n2 2 0\n",
+		"%%MatrixMarket matrix coordinate pattern general\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2 3 0\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2 2 1\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2 2 1\n1 3\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2 2 1\n1 2 3\n",
+		"%%MatrixMarket matrix coordinate integer general\n2 2 1\n1 2 x\n",
+		"%%MatrixMarket matrix coordinate integer symmetric\n2 2 1\n1 2 1\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2 2 0\n1 1\n",
+		"%%MatrixMarket matrix coordinate pattern general\n3000000000 3000000000 0\n",
+		"%%MatrixMarket matrix coordinate pattern general\n2147483647 2147483647 0\n",
+	} {
+		if _, err := ReadMatrixMarket(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadMatrixMarket(%q): no error", s)
+		} else if !strings.HasPrefix(err.Error(), "graph: MatrixMarket") {
+			t.Errorf("ReadMatrixMarket(%q): bad error %q", s, err)
+		}
+	}
+}
+
+func TestWriteMatrixMarket(t *testing.T) {
+	g := New(3)
+	g.AddBoth(0, 1)
+	var buf bytes.Buffer
+	if err := WriteMatrixMarket(&buf, Sort(g)); err != nil {
+		t.Fatalf("WriteMatrixMarket: %v", err)
+	}
+	exp := "%%MatrixMarket matrix coordinate pattern general\n3 3 2\n1 2\n2 1\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteMatrixMarket: %s", mess)
+	}
+
+	for _, g := range []Iterator{New(0), g, Multi{}} {
+		buf.Reset()
+		if err := WriteMatrixMarket(&buf, g); err != nil {
+			t.Fatalf("WriteMatrixMarket: %v", err)
+		}
+		h, err := ReadMatrixMarket(&buf)
+		if err != nil {
+			t.Fatalf("ReadMatrixMarket: %v", err)
+		}
+		if mess, diff := diff(h.String(), String(g)); diff {
+			t.Errorf("MatrixMarket round trip: %s", mess)
+		}
+	}
+}
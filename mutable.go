@@ -59,11 +59,16 @@ func copyMutable(g *Mutable) *Mutable {
 
 func copyImmutable(g *Immutable) *Mutable {
 	h := New(g.Order())
-	for v, neighbors := range g.edges {
-		if deg := len(neighbors); deg > 0 {
+	for v := range h.edges {
+		vertex, cost := g.neighbors(v)
+		if deg := len(vertex); deg > 0 {
 			h.edges[v] = make(map[int]int64, deg)
-			for _, edge := range neighbors {
-				h.edges[v][edge.vertex] = edge.cost
+			for i, w := range vertex {
+				var c int64
+				if cost != nil {
+					c = cost[i]
+				}
+				h.edges[v][int(w)] = c
 			}
 		}
 	}
@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Parse returns the graph described by s, which should have the format
+// produced by the String function: the number of vertices, followed by
+// a list of edges in square brackets. Each element of the list is
+//
+//  • (v w) for an edge from v to w,
+//  • {v w} for the two edges (v, w) and (w, v),
+//
+// optionally followed by :c, where c is the cost of the edge,
+// an integer or one of the words max and min. An element can be
+// prefixed by k×, where k is a positive integer, to denote k identical
+// copies of the edge. The elements are separated by white space.
+//
+// The result is a multigraph if s contains duplicate edges.
+// To bound the memory used for untrusted input, Parse rejects a graph
+// with more than 65536 vertices unless s is at least as long as the
+// number of vertices.
+func Parse(s string) (*Immutable, error) {
+	p := &parser{s: s}
+	p.space()
+	start := p.pos
+	n := p.int()
+	if p.err == nil && (int64(n) > maxImmutableOrder || n > maxIsolated && n > len(s)) {
+		p.pos = start
+		p.fail("too many vertices: " + strconv.Itoa(n))
+	}
+	p.space()
+	p.expect("[")
+	if p.err != nil {
+		return nil, p.err
+	}
+	edges := make([][]neighbor, n)
+	for {
+		p.space()
+		if p.err != nil || p.skip("]") {
+			break
+		}
+		count := 1
+		if i := strings.IndexAny(p.s[p.pos:], "×({"); i > 0 && strings.HasPrefix(p.s[p.pos+i:], "×") {
+			count = p.int()
+			p.expect("×")
+			if p.err == nil && count <= 0 {
+				return nil, p.fail("bad multiplicity " + strconv.Itoa(count))
+			}
+		}
+		var both bool
+		var end string
+		switch {
+		case p.skip("("):
+			end = ")"
+		case p.skip("{"):
+			both, end = true, "}"
+		default:
+			p.fail("expected ( or {")
+		}
+		v := p.vertex(n)
+		p.space()
+		w := p.vertex(n)
+		p.expect(end)
+		var c int64
+		if p.skip(":") {
+			c = p.cost()
+		}
+		if p.err != nil {
+			break
+		}
+		for i := 0; i < count; i++ {
+			edges[v] = append(edges[v], neighbor{w, c})
+			if both && v != w {
+				edges[w] = append(edges[w], neighbor{v, c})
+			}
+		}
+	}
+	p.space()
+	if p.err == nil && p.pos < len(p.s) {
+		p.fail("unexpected " + strconv.Quote(p.s[p.pos:]))
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return newImmutable(edges), nil
+}
+
+// The largest number of vertices that a decoder accepts
+// regardless of the length of its input.
+const maxIsolated = 1 << 16
+
+// parser holds the state of Parse; after the first error,
+// all methods do nothing.
+type parser struct {
+	s   string
+	pos int
+	err error
+}
+
+func (p *parser) fail(msg string) error {
+	if p.err == nil {
+		p.err = errors.New("graph: Parse: position " + strconv.Itoa(p.pos) + ": " + msg)
+	}
+	return p.err
+}
+
+// skip consumes the prefix if present.
+func (p *parser) skip(prefix string) bool {
+	if p.err != nil || !strings.HasPrefix(p.s[p.pos:], prefix) {
+		return false
+	}
+	p.pos += len(prefix)
+	return true
+}
+
+func (p *parser) expect(prefix string) {
+	if !p.skip(prefix) {
+		p.fail("expected " + prefix)
+	}
+}
+
+func (p *parser) space() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// int64 parses an optionally signed decimal integer.
+func (p *parser) int64() int64 {
+	if p.err != nil {
+		return 0
+	}
+	start := p.pos
+	if p.pos < len(p.s) && p.s[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.s) && '0' <= p.s[p.pos] && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	x, err := strconv.ParseInt(p.s[start:p.pos], 10, 64)
+	if err != nil {
+		p.pos = start
+		p.fail("bad number")
+	}
+	return x
+}
+
+func (p *parser) int() int {
+	x := p.int64()
+	if int64(int(x)) != x || x < 0 {
+		p.fail("bad number " + strconv.FormatInt(x, 10))
+		return 0
+	}
+	return int(x)
+}
+
+func (p *parser) vertex(n int) int {
+	start := p.pos
+	v := p.int()
+	if p.err == nil && v >= n {
+		p.pos = start
+		p.fail("vertex out of range: " + strconv.Itoa(v))
+	}
+	return v
+}
+
+func (p *parser) cost() int64 {
+	switch {
+	case p.skip("max"):
+		return Max
+	case p.skip("min"):
+		return Min
+	}
+	return p.int64()
+}
@@ -0,0 +1,74 @@
+package graph
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	g := New(5)
+	g.AddBoth(0, 1)
+	g.AddBothCost(1, 2, Max)
+	g.AddCost(3, 2, Min)
+	g.AddCost(2, 3, -7)
+	g.Add(4, 4)
+	for _, h := range []Iterator{New(0), New(3), g, Multi{}, Sort(g), Transpose(g)} {
+		s := String(h)
+		res, err := Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if !Equal(res, h) {
+			t.Errorf("Parse(%q) = %v", s, res)
+		}
+		if mess, diff := diff(res.String(), s); diff {
+			t.Errorf("Parse: %s", mess)
+		}
+		if Check(res).Multi == 0 {
+			Consistent("Parse", t, res)
+		}
+	}
+
+	res, err := Parse(" 3 [ 2×{2 0}:4\n(1 1) ] ")
+	if err != nil {
+		t.Errorf("Parse: %v", err)
+	}
+	if mess, diff := diff(res.String(), "3 [2×{0 2}:4 (1 1)]"); diff {
+		t.Errorf("Parse: %s", mess)
+	}
+	if mess, diff := diff(Check(res).Multi, 2); diff {
+		t.Errorf("Parse: %s", mess)
+	}
+	if res, err := Parse("65536 []"); err != nil || res.Order() != 65536 {
+		t.Errorf("Parse(%q): %v", "65536 []", err)
+	}
+
+	for _, s := range []string{
+		"",
+		"3",
+		"3 [",
+		"-1 []",
+		"x []",
+		"2 [(0 1)",
+		"2 [(0 2)]",
+		"2 [(0 -1)]",
+		"2 [(0 1]",
+		"2 [{0 1)]",
+		"2 [0×(0 1)]",
+		"2 [2x(0 1)]",
+		"2 [(0 1):]",
+		"2 [(0 1):maximum]",
+		"2 [(0 1):99999999999999999999]",
+		"2 [(0 1)] x",
+		"9999999999999 []",
+		"2147483647 []",
+		"65537 []",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): no error", s)
+		}
+	}
+
+	_, err = Parse("2 [(0 1) (1 2)]")
+	if mess, diff := diff(err.Error(), "graph: Parse: position 12: vertex out of range: 2"); diff {
+		t.Errorf("Parse: %s", mess)
+	}
+}
@@ -0,0 +1,220 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+)
+
+// Power iterations stop when the total change of a vector is at most
+// tolerance times the number of vertices, or after maxIterations steps.
+const (
+	maxIterations = 1000
+	tolerance     = 1e-12
+)
+
+// EigenvectorCentrality computes the eigenvector centrality of each vertex
+// in g: a non-negative vector x of unit length such that x[v] is proportional
+// to the sum of x[w] over all edges from w to v. If g isn't strongly
+// connected, the result may depend on more than one eigenvector.
+// Edge costs are disregarded and multiple edges are counted separately.
+//
+// The implementation uses power iteration on A + I, where A is the
+// adjacency matrix, which has the same eigenvectors as A but also
+// converges for bipartite graphs. Each iteration takes O(|E| + |V|) time,
+// where |E| is the number of edges and |V| the number of vertices.
+func EigenvectorCentrality(g Iterator) []float64 {
+	n := g.Order()
+	x := make([]float64, n)
+	if n == 0 {
+		return x
+	}
+	for v := range x {
+		x[v] = 1 / math.Sqrt(float64(n))
+	}
+	next := make([]float64, n)
+	for i := 0; i < maxIterations; i++ {
+		copy(next, x)
+		for v := range x {
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				next[w] += x[v]
+				return
+			})
+		}
+		normalize(next, euclideanNorm(next))
+		x, next = next, x
+		if distance(x, next) <= tolerance*float64(n) {
+			break
+		}
+	}
+	return x
+}
+
+// PageRank computes the PageRank of each vertex in g, a probability
+// distribution that sums to 1. A random surfer follows a random edge
+// from the current vertex with probability damping, and otherwise jumps
+// to a random vertex. A vertex without outgoing edges is always left by a jump.
+// Edge costs are disregarded and multiple edges are counted separately.
+//
+// The jumps are distributed according to personalization, a list of
+// non-negative weights, one for each vertex; they needn't sum to 1.
+// If personalization is nil, the jumps are uniformly distributed.
+// PageRank panics if damping isn't in [0, 1], or if personalization
+// has the wrong length, a negative weight, or only zero weights.
+// The damping factor is typically 0.85.
+//
+// The implementation uses power iteration. Each iteration takes
+// O(|E| + |V|) time, where |E| is the number of edges and |V| the number
+// of vertices.
+func PageRank(g Iterator, damping float64, personalization []float64) []float64 {
+	n := g.Order()
+	if damping < 0 || damping > 1 {
+		panic("damping factor out of range: " + strconv.FormatFloat(damping, 'g', -1, 64))
+	}
+	if personalization != nil && len(personalization) != n {
+		panic("personalization vector has wrong length: " + strconv.Itoa(len(personalization)))
+	}
+	x := make([]float64, n)
+	if n == 0 {
+		return x
+	}
+	jump := make([]float64, n)
+	if personalization == nil {
+		for v := range jump {
+			jump[v] = 1
+		}
+	} else {
+		copy(jump, personalization)
+	}
+	total := 0.0
+	for _, p := range jump {
+		if p < 0 {
+			panic("negative personalization weight")
+		}
+		total += p
+	}
+	if total == 0 {
+		panic("personalization vector is zero")
+	}
+	normalize(jump, total)
+
+	outdegree := make([]int, n)
+	for v := range outdegree {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			outdegree[v]++
+			return
+		})
+	}
+	copy(x, jump)
+	next := make([]float64, n)
+	for i := 0; i < maxIterations; i++ {
+		// The probability of jumping, from a dangling vertex or otherwise.
+		jumping := 1 - damping
+		for v := range next {
+			next[v] = 0
+			if outdegree[v] == 0 {
+				jumping += damping * x[v]
+			}
+		}
+		for v := range x {
+			if outdegree[v] == 0 {
+				continue
+			}
+			share := damping * x[v] / float64(outdegree[v])
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				next[w] += share
+				return
+			})
+		}
+		for v := range next {
+			next[v] += jumping * jump[v]
+		}
+		x, next = next, x
+		if distance(x, next) <= tolerance*float64(n) {
+			break
+		}
+	}
+	return x
+}
+
+// HITS computes the hub and authority scores of each vertex in g using
+// Kleinberg's hyperlink-induced topic search. A good hub points to many
+// good authorities, and a good authority is pointed to by many good hubs:
+// authorities[v] is proportional to the sum of hubs[w] over all edges
+// from w to v, and hubs[v] is proportional to the sum of authorities[w]
+// over all edges from v to w. Both lists sum to 1, unless g has no edges,
+// in which case all scores are 0.
+// Edge costs are disregarded and multiple edges are counted separately.
+//
+// The implementation uses power iteration. Each iteration takes
+// O(|E| + |V|) time, where |E| is the number of edges and |V| the number
+// of vertices.
+func HITS(g Iterator) (hubs, authorities []float64) {
+	n := g.Order()
+	hubs = make([]float64, n)
+	authorities = make([]float64, n)
+	if n == 0 {
+		return
+	}
+	for v := range hubs {
+		hubs[v] = 1 / float64(n)
+	}
+	prev := make([]float64, n)
+	for i := 0; i < maxIterations; i++ {
+		for v := range authorities {
+			authorities[v] = 0
+		}
+		for v := range hubs {
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				authorities[w] += hubs[v]
+				return
+			})
+		}
+		copy(prev, hubs)
+		for v := range hubs {
+			hubs[v] = 0
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				hubs[v] += authorities[w]
+				return
+			})
+		}
+		normalize(authorities, sum(authorities))
+		normalize(hubs, sum(hubs))
+		if distance(hubs, prev) <= tolerance*float64(n) {
+			break
+		}
+	}
+	return
+}
+
+// normalize divides each element of x by norm, unless norm is zero.
+func normalize(x []float64, norm float64) {
+	if norm == 0 {
+		return
+	}
+	for i := range x {
+		x[i] /= norm
+	}
+}
+
+func sum(x []float64) (s float64) {
+	for _, a := range x {
+		s += a
+	}
+	return
+}
+
+func euclideanNorm(x []float64) float64 {
+	s := 0.0
+	for _, a := range x {
+		s += a * a
+	}
+	return math.Sqrt(s)
+}
+
+// distance returns the L1 distance between x and y.
+func distance(x, y []float64) (d float64) {
+	for i := range x {
+		d += math.Abs(x[i] - y[i])
+	}
+	return
+}
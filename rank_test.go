@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEigenvectorCentrality(t *testing.T) {
+	checkFloats("EigenvectorCentrality", t, EigenvectorCentrality(New(0)), []float64{})
+
+	g := New(4)
+	for v := 0; v < 4; v++ {
+		for w := v + 1; w < 4; w++ {
+			g.AddBoth(v, w)
+		}
+	}
+	checkFloats("EigenvectorCentrality K4", t, EigenvectorCentrality(g), []float64{0.5, 0.5, 0.5, 0.5})
+
+	// A star is bipartite; plain power iteration would oscillate.
+	g = New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(0, 2)
+	g.AddBoth(0, 3)
+	a, b := 1/math.Sqrt(2), 1/math.Sqrt(6)
+	checkFloats("EigenvectorCentrality star", t, EigenvectorCentrality(g), []float64{a, b, b, b})
+
+	for i := 0; i < 20; i++ {
+		n := 2 + rand.Intn(20)
+		g := randomGraph(n, 2*n)
+		for v := 0; v < n; v++ {
+			g.AddBoth(v, (v+1)%n)
+		}
+		x := EigenvectorCentrality(g)
+		if mess, diff := diff(len(x), n); diff {
+			t.Errorf("EigenvectorCentrality: %s", mess)
+		}
+		y := make([]float64, n)
+		for v := range x {
+			g.Visit(v, func(w int, _ int64) (skip bool) {
+				y[w] += x[v]
+				return
+			})
+		}
+		lambda := 0.0
+		for v := range x {
+			lambda += x[v] * y[v]
+		}
+		for v := range y {
+			y[v] /= lambda
+		}
+		checkFloats("EigenvectorCentrality eigenvector", t, y, x)
+		if norm := euclideanNorm(x); math.Abs(norm-1) > 1e-9 {
+			t.Errorf("EigenvectorCentrality: norm %v", norm)
+		}
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	checkFloats("PageRank", t, PageRank(New(0), 0.85, nil), []float64{})
+
+	g := New(4)
+	for v := 0; v < 4; v++ {
+		g.Add(v, (v+1)%4)
+	}
+	checkFloats("PageRank cycle", t, PageRank(g, 0.85, nil), []float64{0.25, 0.25, 0.25, 0.25})
+	checkFloats("PageRank no damping", t, PageRank(g, 0, []float64{1, 0, 3, 0}), []float64{0.25, 0, 0.75, 0})
+
+	// Two vertices pointing to a dangling vertex.
+	g = New(3)
+	g.Add(0, 2)
+	g.Add(1, 2)
+	d := 0.5
+	res := PageRank(g, d, nil)
+	checkPageRank("PageRank dangling", t, g, d, nil, res)
+	if !(res[2] > res[0] && math.Abs(res[0]-res[1]) < 1e-9) {
+		t.Errorf("PageRank dangling: %v", res)
+	}
+
+	for i := 0; i < 20; i++ {
+		n := 1 + rand.Intn(20)
+		g := New(n)
+		for j := rand.Intn(3 * n); j > 0; j-- {
+			g.Add(rand.Intn(n), rand.Intn(n))
+		}
+		p := make([]float64, n)
+		for v := range p {
+			p[v] = float64(rand.Intn(3))
+		}
+		p[rand.Intn(n)]++
+		checkPageRank("PageRank", t, g, 0.85, nil, PageRank(g, 0.85, nil))
+		checkPageRank("PageRank personalized", t, g, 0.85, p, PageRank(g, 0.85, p))
+	}
+}
+
+// checkPageRank checks that x is a probability distribution
+// and a fixed point of a PageRank iteration step.
+func checkPageRank(mess string, t *testing.T, g Iterator, d float64, p []float64, x []float64) {
+	n := g.Order()
+	jump := make([]float64, n)
+	for v := range jump {
+		jump[v] = 1
+		if p != nil {
+			jump[v] = p[v]
+		}
+	}
+	normalize(jump, sum(jump))
+	y := make([]float64, n)
+	for v := 0; v < n; v++ {
+		deg := 0
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			deg++
+			return
+		})
+		if deg == 0 {
+			for w := range y {
+				y[w] += d * x[v] * jump[w]
+			}
+			continue
+		}
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			y[w] += d * x[v] / float64(deg)
+			return
+		})
+	}
+	for v := range y {
+		y[v] += (1 - d) * jump[v]
+	}
+	checkFloats(mess, t, x, y)
+	if s := sum(x); math.Abs(s-1) > 1e-9 {
+		t.Errorf("%s: sum %v", mess, s)
+	}
+}
+
+func TestHITS(t *testing.T) {
+	hubs, authorities := HITS(New(2))
+	checkFloats("HITS hubs", t, hubs, []float64{0, 0})
+	checkFloats("HITS authorities", t, authorities, []float64{0, 0})
+
+	// Two hubs pointing to two authorities.
+	g := New(4)
+	g.Add(0, 2)
+	g.Add(0, 3)
+	g.Add(1, 2)
+	g.Add(1, 3)
+	hubs, authorities = HITS(g)
+	checkFloats("HITS hubs", t, hubs, []float64{0.5, 0.5, 0, 0})
+	checkFloats("HITS authorities", t, authorities, []float64{0, 0, 0.5, 0.5})
+
+	// The authority of 2 is pointed to by both hubs.
+	g = New(4)
+	g.Add(0, 2)
+	g.Add(1, 2)
+	g.Add(1, 3)
+	hubs, authorities = HITS(g)
+	// The hub vector is the principal eigenvector of AAᵀ = [[1 1] [1 2]].
+	phi := (1 + math.Sqrt(5)) / 2
+	h0, h1 := 1/(1+phi), phi/(1+phi)
+	a2, a3 := h0+h1, h1
+	checkFloats("HITS hubs", t, hubs, []float64{h0, h1, 0, 0})
+	checkFloats("HITS authorities", t, authorities, []float64{0, 0, a2 / (a2 + a3), a3 / (a2 + a3)})
+}
+
+func BenchmarkPageRank(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := New(n)
+	for i := 0; i < 10*n; i++ {
+		g.Add(rand.Intn(n), rand.Intn(n))
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = PageRank(g, 0.85, nil)
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ReadSNAP reads a directed graph from an edge list in the style of the
+// Stanford Network Analysis Project (SNAP) datasets. Each line "u v"
+// describes an edge from u to v, where u and v are non-negative integers
+// separated by white space. An optional third field gives the cost of
+// the edge. Lines starting with # are comments.
+//
+// The vertex ids in the file need not be consecutive. They are sorted
+// and numbered from 0 to n-1, and ids[v] is the id of vertex v.
+// Only vertices with at least one edge are included in the graph.
+//
+// The edges are kept in a list of pairs while reading, which takes
+// time O(|E| log |E|) and space O(|E|) to convert to neighbor lists.
+func ReadSNAP(r io.Reader) (g *Immutable, ids []int, err error) {
+	in := newLineReader(r, "SNAP")
+	var ends []int
+	var costs []int64
+	for {
+		fields, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(fields) == 0 || fields[0][0] == '#' {
+			continue
+		}
+		if len(fields) > 3 || len(fields) < 2 {
+			return nil, nil, in.errorf("expected u v [cost]")
+		}
+		for _, s := range fields[:2] {
+			v, err := in.int(s)
+			if err != nil {
+				return nil, nil, err
+			}
+			ends = append(ends, v)
+		}
+		var c int64
+		if len(fields) == 3 {
+			if c, err = in.cost(fields[2]); err != nil {
+				return nil, nil, err
+			}
+		}
+		if c != 0 && costs == nil {
+			costs = make([]int64, len(ends)/2-1, cap(ends)/2)
+		}
+		if costs != nil {
+			costs = append(costs, c)
+		}
+	}
+
+	ids = append([]int(nil), ends...)
+	sort.Ints(ids)
+	n := 0
+	for i, id := range ids {
+		if i == 0 || id != ids[n-1] {
+			ids[n] = id
+			n++
+		}
+	}
+	ids = ids[:n:n]
+	edges := make([][]neighbor, n)
+	for i := 0; i < len(ends); i += 2 {
+		v := sort.SearchInts(ids, ends[i])
+		w := sort.SearchInts(ids, ends[i+1])
+		var c int64
+		if costs != nil {
+			c = costs[i/2]
+		}
+		edges[v] = append(edges[v], neighbor{w, c})
+	}
+	return newImmutable(edges), ids, nil
+}
+
+// WriteSNAP writes g as an edge list in the format described for ReadSNAP,
+// with a comment giving the number of vertices and edges. The costs are
+// written only if g has edges of non-zero cost. Isolated vertices are
+// lost when the list is read back.
+func WriteSNAP(w io.Writer, g Iterator) error {
+	n := g.Order()
+	weighted := hasCost(g)
+	out := bufio.NewWriter(w)
+	out.WriteString("# Nodes: " + strconv.Itoa(n) + " Edges: " + strconv.Itoa(edgeCount(g)) + "\n")
+	if weighted {
+		out.WriteString("# FromNodeId\tToNodeId\tCost\n")
+	} else {
+		out.WriteString("# FromNodeId\tToNodeId\n")
+	}
+	for v := 0; v < n; v++ {
+		g.Visit(v, func(w int, c int64) (skip bool) {
+			out.WriteString(strconv.Itoa(v) + "\t" + strconv.Itoa(w))
+			if weighted {
+				out.WriteString("\t" + strconv.FormatInt(c, 10))
+			}
+			out.WriteString("\n")
+			return
+		})
+	}
+	return out.Flush()
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadSNAP(t *testing.T) {
+	in := `# Directed graph: sample.txt
+# Nodes: 3 Edges: 4
+# FromNodeId	ToNodeId
+10	20
+20 10
+
+10	100
+100  100
+`
+	g, ids, err := ReadSNAP(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadSNAP: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [{0 1} (0 2) (2 2)]"); diff {
+		t.Errorf("ReadSNAP: %s", mess)
+	}
+	if mess, diff := diff(ids, []int{10, 20, 100}); diff {
+		t.Errorf("ReadSNAP: %s", mess)
+	}
+
+	g, ids, err = ReadSNAP(strings.NewReader("0 1\n1 2 5\n1 2\n"))
+	if err != nil {
+		t.Fatalf("ReadSNAP: %v", err)
+	}
+	if mess, diff := diff(g.String(), "3 [(0 1) (1 2) (1 2):5]"); diff {
+		t.Errorf("ReadSNAP: %s", mess)
+	}
+	if mess, diff := diff(ids, []int{0, 1, 2}); diff {
+		t.Errorf("ReadSNAP: %s", mess)
+	}
+
+	g, ids, err = ReadSNAP(strings.NewReader("# empty\n"))
+	if err != nil {
+		t.Fatalf("ReadSNAP: %v", err)
+	}
+	if g.Order() != 0 || len(ids) != 0 {
+		t.Errorf("ReadSNAP: %v, %v; want empty graph", g, ids)
+	}
+
+	for _, s := range []string{
+		"1\n",
+		"1 2 3 4\n",
+		"1 -2\n",
+		"1 x\n",
+		"1 2 x\n",
+	} {
+		if _, _, err := ReadSNAP(strings.NewReader(s)); err == nil {
+			t.Errorf("ReadSNAP(%q): no error", s)
+		} else if !strings.HasPrefix(err.Error(), "graph: SNAP line 1: ") {
+			t.Errorf("ReadSNAP(%q): bad error %q", s, err)
+		}
+	}
+}
+
+func TestWriteSNAP(t *testing.T) {
+	g := New(3)
+	g.AddBoth(0, 1)
+	g.Add(2, 2)
+	var buf bytes.Buffer
+	if err := WriteSNAP(&buf, g); err != nil {
+		t.Fatalf("WriteSNAP: %v", err)
+	}
+	exp := "# Nodes: 3 Edges: 3\n# FromNodeId\tToNodeId\n0\t1\n1\t0\n2\t2\n"
+	if mess, diff := diff(buf.String(), exp); diff {
+		t.Errorf("WriteSNAP: %s", mess)
+	}
+
+	buf.Reset()
+	if err := WriteSNAP(&buf, Multi{}); err != nil {
+		t.Fatalf("WriteSNAP: %v", err)
+	}
+	h, _, err := ReadSNAP(&buf)
+	if err != nil {
+		t.Fatalf("ReadSNAP: %v", err)
+	}
+	// The isolated vertex 2 is lost.
+	exp = "2 [3×(0 0) 2×(0 0):5 4×{0 1}:5 (0 1):5 (0 1):7]"
+	if mess, diff := diff(h.String(), exp); diff {
+		t.Errorf("SNAP round trip: %s", mess)
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+// Triangles returns the number of triangles containing each vertex in g.
+// A triangle is a set of three vertices that are all adjacent to each other.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The implementation orients each edge from the vertex of lower degree
+// to the vertex of higher degree, and counts the triangles by merging
+// the sorted lists of outward neighbors at the two ends of each edge.
+// If g is an *Immutable with an edge (w, v) for each edge (v, w), and without
+// self-loops or multiple edges, its sorted neighbor lists are used directly.
+// The time complexity is O(|E|^1.5 + |V|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func Triangles(g Iterator) []int {
+	return triangles(undirectedSimple(g))
+}
+
+// triangles counts the triangles in the undirected simple graph g.
+func triangles(g *Immutable) []int {
+	n := g.Order()
+	// Order the vertices by degree, breaking ties by number.
+	precedes := func(v, w int) bool {
+		dv, dw := g.Degree(v), g.Degree(w)
+		return dv < dw || dv == dw && v < w
+	}
+	out := make([][]int32, n)
+	for v := range out {
+		neighbors, _ := g.neighbors(v)
+		for _, w := range neighbors {
+			if precedes(v, int(w)) {
+				out[v] = append(out[v], w)
+			}
+		}
+	}
+	count := make([]int, n)
+	for v, neighbors := range out {
+		for _, w := range neighbors {
+			// Each triangle is found once, from its lowest vertex v.
+			a, b := out[v], out[w]
+			for i, j := 0, 0; i < len(a) && j < len(b); {
+				switch {
+				case a[i] < b[j]:
+					i++
+				case a[i] > b[j]:
+					j++
+				default:
+					count[v]++
+					count[w]++
+					count[a[i]]++
+					i++
+					j++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// LocalClustering returns the local clustering coefficient of each vertex
+// in g: the fraction of pairs of neighbors of v that are adjacent to each
+// other. The coefficient of a vertex with less than two neighbors is 0.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The time complexity is O(|E|^1.5 + |V|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func LocalClustering(g Iterator) []float64 {
+	h := undirectedSimple(g)
+	res := make([]float64, h.Order())
+	for v, t := range triangles(h) {
+		if d := h.Degree(v); d >= 2 {
+			res[v] = float64(t) / float64(d*(d-1)/2)
+		}
+	}
+	return res
+}
+
+// GlobalClustering returns the global clustering coefficient of g,
+// also known as its transitivity: the fraction of paths of length two
+// that are closed into a triangle. It's 0 if there are no such paths.
+// Edges are treated as undirected, and self-loops and multiple edges
+// are disregarded.
+//
+// The time complexity is O(|E|^1.5 + |V|), where |E| is the number of edges
+// and |V| the number of vertices in the graph.
+func GlobalClustering(g Iterator) float64 {
+	h := undirectedSimple(g)
+	closed, paths := 0, 0
+	for v, t := range triangles(h) {
+		d := h.Degree(v)
+		closed += t
+		paths += d * (d - 1) / 2
+	}
+	if paths == 0 {
+		return 0
+	}
+	return float64(closed) / float64(paths)
+}
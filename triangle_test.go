@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTriangles(t *testing.T) {
+	if mess, diff := diff(Triangles(New(0)), []int{}); diff {
+		t.Errorf("Triangles: %s", mess)
+	}
+
+	// A triangle with a pendant vertex, a loop and a parallel edge.
+	g := New(4)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	g.Add(2, 0)
+	g.AddBoth(2, 3)
+	g.Add(3, 3)
+	for _, h := range []Iterator{g, Sort(g)} {
+		if mess, diff := diff(Triangles(h), []int{1, 1, 1, 0}); diff {
+			t.Errorf("Triangles: %s", mess)
+		}
+		if mess, diff := diff(LocalClustering(h), []float64{1, 1, 1.0 / 3, 0}); diff {
+			t.Errorf("LocalClustering: %s", mess)
+		}
+		if mess, diff := diff(GlobalClustering(h), 3.0/5); diff {
+			t.Errorf("GlobalClustering: %s", mess)
+		}
+	}
+	if mess, diff := diff(GlobalClustering(New(3)), 0.0); diff {
+		t.Errorf("GlobalClustering: %s", mess)
+	}
+
+	for i := 0; i < 50; i++ {
+		n := 1 + rand.Intn(15)
+		g := New(n)
+		for j := rand.Intn(1 + n*n/2); j > 0; j-- {
+			g.Add(rand.Intn(n), rand.Intn(n))
+		}
+		exp := bruteTriangles(g)
+		if mess, diff := diff(Triangles(g), exp); diff {
+			t.Errorf("Triangles %v: %s", g, mess)
+		}
+		if mess, diff := diff(Triangles(Sort(g)), exp); diff {
+			t.Errorf("Triangles %v: %s", g, mess)
+		}
+		h := randomGraph(n, n*n/3)
+		if mess, diff := diff(Triangles(Sort(h)), bruteTriangles(h)); diff {
+			t.Errorf("Triangles %v: %s", h, mess)
+		}
+	}
+}
+
+// Compare the Immutable fast path with the generic path on the same graph.
+func TestTrianglesImmutable(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		g := randomSimpleGraph(1+rand.Intn(20), 60)
+		h := Sort(g)
+		if undirectedSimple(h) != h {
+			t.Fatalf("undirectedSimple(%v): fast path not used", h)
+		}
+		if mess, diff := diff(Triangles(h), Triangles(g)); diff {
+			t.Errorf("Triangles %v: %s", g, mess)
+		}
+		if mess, diff := diff(Triangles(h), bruteTriangles(g)); diff {
+			t.Errorf("Triangles %v: %s", g, mess)
+		}
+		if mess, diff := diff(LocalClustering(h), LocalClustering(g)); diff {
+			t.Errorf("LocalClustering %v: %s", g, mess)
+		}
+		if mess, diff := diff(GlobalClustering(h), GlobalClustering(g)); diff {
+			t.Errorf("GlobalClustering %v: %s", g, mess)
+		}
+		if mess, diff := diff(CoreNumbers(h), CoreNumbers(g)); diff {
+			t.Errorf("CoreNumbers %v: %s", g, mess)
+		}
+		if mess, diff := diff(CoreNumbers(h), bruteCore(undirected(g))); diff {
+			t.Errorf("CoreNumbers %v: %s", g, mess)
+		}
+	}
+}
+
+// randomSimpleGraph returns an undirected graph with n vertices and
+// at most m edges, without self-loops.
+func randomSimpleGraph(n, m int) *Mutable {
+	g := New(n)
+	for i := 0; i < m; i++ {
+		if v, w := rand.Intn(n), rand.Intn(n); v != w {
+			g.AddBoth(v, w)
+		}
+	}
+	return g
+}
+
+func bruteTriangles(g Iterator) []int {
+	n := g.Order()
+	adj := make([][]bool, n)
+	for v := range adj {
+		adj[v] = make([]bool, n)
+	}
+	for v := range adj {
+		g.Visit(v, func(w int, _ int64) (skip bool) {
+			adj[v][w], adj[w][v] = true, true
+			return
+		})
+	}
+	count := make([]int, n)
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			for w := v + 1; w < n; w++ {
+				if adj[u][v] && adj[v][w] && adj[u][w] {
+					count[u]++
+					count[v]++
+					count[w]++
+				}
+			}
+		}
+	}
+	return count
+}
+
+func BenchmarkTriangles(b *testing.B) {
+	n := 1000
+	b.StopTimer()
+	g := Sort(randomGraph(n, 10*n))
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Triangles(g)
+	}
+}
@@ -0,0 +1,233 @@
+package graph
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TSP computes a shortest Hamiltonian cycle, a solution to the
+// travelling salesman problem, in a weighted directed graph.
+// The tour starts at vertex 0, and the closing edge back to 0 is implied;
+// cost is the total cost of the edges in the cycle, including the closing edge.
+// If g contains multiple edges from v to w, the cheapest one is used.
+// If no Hamiltonian cycle exists, tour is empty and cost is 0.
+//
+// The implementation uses the Held–Karp dynamic programming algorithm.
+// The time complexity is O(2ⁿ⋅n²) and the space complexity O(2ⁿ⋅n),
+// where n is the number of vertices in the graph.
+// This is practical only for small graphs, with n ≤ 20 or so;
+// the function panics if n > 25, which would need more than 3 GB.
+// For larger graphs, use TSPApprox.
+func TSP(g Iterator) (tour []int, cost int64) {
+	n := g.Order()
+	switch {
+	case n == 0:
+		return []int{}, 0
+	case n == 1:
+		return []int{0}, 0
+	case n > maxExact:
+		panic("too many vertices for exact search: n=" + strconv.Itoa(n))
+	}
+	c := newCostTable(g)
+
+	// Vertex 0 is the starting point of all tours.
+	// The subsets S of the remaining vertices 1..n-1 are represented
+	// by bit masks where bit i-1 is set iff vertex i belongs to S.
+	// dist[S⋅m + i-1] is the cost of a shortest path from 0 that
+	// visits exactly the vertices in S and ends at i, or Max if none exists.
+	m := n - 1
+	all := 1<<uint(m) - 1
+	dist := make([]int64, (all+1)*m)
+	for i := range dist {
+		dist[i] = Max
+	}
+	for i := 1; i < n; i++ {
+		if d, ok := c.cost(0, i); ok {
+			dist[(1<<uint(i-1))*m+i-1] = d
+		}
+	}
+	for S := 1; S < all; S++ {
+		for i := 1; i < n; i++ {
+			d := dist[S*m+i-1]
+			if d == Max {
+				continue
+			}
+			for _, e := range c.edges[i] {
+				w := e.vertex
+				if w == 0 || S&(1<<uint(w-1)) != 0 {
+					continue
+				}
+				T := S | 1<<uint(w-1)
+				if alt := d + e.cost; alt < dist[T*m+w-1] {
+					dist[T*m+w-1] = alt
+				}
+			}
+		}
+	}
+
+	// Close the cycle.
+	last := -1
+	for i := 1; i < n; i++ {
+		d := dist[all*m+i-1]
+		if d == Max {
+			continue
+		}
+		if back, ok := c.cost(i, 0); ok && (last == -1 || d+back < cost) {
+			last, cost = i, d+back
+		}
+	}
+	if last == -1 {
+		return []int{}, 0
+	}
+
+	// Walk backwards from the last vertex.
+	tour = make([]int, n)
+	for S, w, k := all, last, n-1; k > 0; k-- {
+		tour[k] = w
+		d := dist[S*m+w-1]
+		S &^= 1 << uint(w-1)
+		if S == 0 {
+			break
+		}
+		for i := 1; i < n; i++ {
+			if S&(1<<uint(i-1)) == 0 || dist[S*m+i-1] == Max {
+				continue
+			}
+			if d0, ok := c.cost(i, w); ok && dist[S*m+i-1]+d0 == d {
+				w = i
+				break
+			}
+		}
+	}
+	return tour, cost
+}
+
+// TSPApprox computes a short Hamiltonian cycle in a weighted directed graph.
+// The tour starts at vertex 0, and the closing edge back to 0 is implied;
+// cost is the total cost of the edges in the cycle, including the closing edge.
+// If g contains multiple edges from v to w, the cheapest one is used.
+//
+// The tour is constructed by the nearest neighbor heuristic and then
+// improved by 2-opt moves until no further improvement is possible.
+// The result is not necessarily optimal. If the heuristic doesn't find
+// a Hamiltonian cycle, tour is empty and cost is 0; this may happen
+// even if such a cycle exists, unless g is a complete graph.
+//
+// Each round of 2-opt moves takes O(n²⋅log n) time,
+// where n is the number of vertices in the graph.
+func TSPApprox(g Iterator) (tour []int, cost int64) {
+	n := g.Order()
+	switch n {
+	case 0:
+		return []int{}, 0
+	case 1:
+		return []int{0}, 0
+	}
+	c := newCostTable(g)
+
+	// Nearest neighbor
+	visited := make([]bool, n)
+	tour = make([]int, 0, n)
+	for v := 0; ; {
+		tour = append(tour, v)
+		visited[v] = true
+		if len(tour) == n {
+			break
+		}
+		next, best := -1, int64(0)
+		for _, e := range c.edges[v] {
+			if !visited[e.vertex] && (next == -1 || e.cost < best) {
+				next, best = e.vertex, e.cost
+			}
+		}
+		if next == -1 {
+			return []int{}, 0
+		}
+		v = next
+	}
+	if _, ok := c.cost(tour[n-1], 0); !ok {
+		return []int{}, 0
+	}
+
+	// 2-opt: replace the edges (t[i], t[i+1]) and (t[j], t[j+1])
+	// with (t[i], t[j]) and (t[i+1], t[j+1]), reversing the path
+	// t[i+1], …, t[j] in between.
+	at := func(i int) int { return tour[i%n] }
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			a, b := at(i), at(i+1)
+			ab, _ := c.cost(a, b)
+			// fwd and rev are the costs of the path from t[i+1] to t[j]
+			// in the forward and reverse directions.
+			var fwd, rev int64
+			for j := i + 2; j < n; j++ {
+				p, q := at(j-1), at(j)
+				pq, ok1 := c.cost(p, q)
+				qp, ok2 := c.cost(q, p)
+				if !ok1 || !ok2 {
+					break
+				}
+				fwd, rev = fwd+pq, rev+qp
+				if i == 0 && j == n-1 {
+					continue // Both edges are adjacent to t[0].
+				}
+				d := at(j + 1)
+				qd, _ := c.cost(q, d)
+				aq, ok1 := c.cost(a, q)
+				bd, ok2 := c.cost(b, d)
+				if !ok1 || !ok2 {
+					continue
+				}
+				if aq+rev+bd < ab+fwd+qd {
+					for l, r := i+1, j; l < r; l, r = l+1, r-1 {
+						tour[l], tour[r] = tour[r], tour[l]
+					}
+					improved = true
+					break
+				}
+			}
+		}
+	}
+
+	for i := range tour {
+		d, _ := c.cost(tour[i], at(i+1))
+		cost += d
+	}
+	return tour, cost
+}
+
+// costTable holds the cheapest edge from v to w, v ≠ w,
+// in a sorted list for each vertex v.
+type costTable struct {
+	edges [][]neighbor
+}
+
+func newCostTable(g Iterator) *costTable {
+	h := Sort(g)
+	t := &costTable{edges: make([][]neighbor, h.Order())}
+	for v := range t.edges {
+		prev := -1
+		h.Visit(v, func(w int, c int64) (skip bool) {
+			// Neighbors are sorted by (vertex, cost); keep the first one.
+			if w == v || w == prev {
+				return
+			}
+			prev = w
+			t.edges[v] = append(t.edges[v], neighbor{w, c})
+			return
+		})
+	}
+	return t
+}
+
+// cost returns the cost of the cheapest edge from v to w, if any.
+func (t *costTable) cost(v, w int) (c int64, ok bool) {
+	edges := t.edges[v]
+	n := len(edges)
+	i := sort.Search(n, func(i int) bool { return w <= edges[i].vertex })
+	if i < n && edges[i].vertex == w {
+		return edges[i].cost, true
+	}
+	return 0, false
+}
@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTSP(t *testing.T) {
+	g := New(0)
+	tour, cost := TSP(g)
+	if mess, diff := diff(tour, []int{}); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(0)); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+
+	g = New(1)
+	tour, cost = TSP(g)
+	if mess, diff := diff(tour, []int{0}); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(0)); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+
+	g = New(4)
+	g.AddBothCost(0, 1, 10)
+	g.AddBothCost(0, 2, 15)
+	g.AddBothCost(0, 3, 20)
+	g.AddBothCost(1, 2, 35)
+	g.AddBothCost(1, 3, 25)
+	g.AddBothCost(2, 3, 30)
+	tour, cost = TSP(g)
+	if mess, diff := diff(tour, []int{0, 2, 3, 1}); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(80)); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+
+	// A one-way street.
+	g.AddCost(2, 0, 1)
+	g.Delete(0, 2)
+	tour, cost = TSP(g)
+	if mess, diff := diff(tour, []int{0, 1, 3, 2}); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(66)); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+
+	g.Delete(3, 2)
+	g.Delete(1, 2)
+	tour, cost = TSP(g)
+	if mess, diff := diff(tour, []int{}); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(0)); diff {
+		t.Errorf("TSP: %s", mess)
+	}
+
+	// Compare with brute force.
+	for i := 0; i < 20; i++ {
+		n := 2 + rand.Intn(6)
+		g := New(n)
+		for j := 0; j < n*n/2; j++ {
+			g.AddCost(rand.Intn(n), rand.Intn(n), int64(rand.Intn(10)))
+		}
+		tour, cost := TSP(g)
+		exp, ok := bruteTSP(g)
+		if mess, diff := diff(len(tour) > 0, ok); diff {
+			t.Errorf("TSP %v: %s", g, mess)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		checkPath("TSP", t, g, tour, true)
+		if mess, diff := diff(cost, exp); diff {
+			t.Errorf("TSP %v: %s", g, mess)
+		}
+		if mess, diff := diff(cost, tourCost(g, tour)); diff {
+			t.Errorf("TSP %v: %s", g, mess)
+		}
+	}
+}
+
+func TestTSPApprox(t *testing.T) {
+	g := New(0)
+	tour, cost := TSPApprox(g)
+	if mess, diff := diff(tour, []int{}); diff {
+		t.Errorf("TSPApprox: %s", mess)
+	}
+	if mess, diff := diff(cost, int64(0)); diff {
+		t.Errorf("TSPApprox: %s", mess)
+	}
+
+	g = New(1)
+	tour, cost = TSPApprox(g)
+	if mess, diff := diff(tour, []int{0}); diff {
+		t.Errorf("TSPApprox: %s", mess)
+	}
+
+	g = New(3)
+	g.AddBoth(0, 1)
+	g.AddBoth(1, 2)
+	tour, cost = TSPApprox(g)
+	if mess, diff := diff(tour, []int{}); diff {
+		t.Errorf("TSPApprox: %s", mess)
+	}
+
+	// Points on a circle, visited in a scrambled order.
+	n := 12
+	g = New(n)
+	for v := 0; v < n; v++ {
+		for w := 0; w < n; w++ {
+			if v != w {
+				d := (5*v - 5*w + 5*n) % n
+				g.AddCost(v, w, int64(min(d, n-d)))
+			}
+		}
+	}
+	tour, cost = TSPApprox(g)
+	checkPath("TSPApprox", t, g, tour, true)
+	if mess, diff := diff(cost, int64(n)); diff {
+		t.Errorf("TSPApprox: %s", mess)
+	}
+
+	for i := 0; i < 20; i++ {
+		n := 2 + rand.Intn(8)
+		g := New(n)
+		for v := 0; v < n; v++ {
+			for w := 0; w < n; w++ {
+				if v != w {
+					g.AddCost(v, w, int64(rand.Intn(100)))
+				}
+			}
+		}
+		tour, cost := TSPApprox(g)
+		checkPath("TSPApprox", t, g, tour, true)
+		if mess, diff := diff(cost, tourCost(g, tour)); diff {
+			t.Errorf("TSPApprox %v: %s", g, mess)
+		}
+		if _, opt := TSP(g); cost < opt {
+			t.Errorf("TSPApprox %v: %d < optimal %d", g, cost, opt)
+		}
+	}
+}
+
+func tourCost(g *Mutable, tour []int) (cost int64) {
+	for i, v := range tour {
+		cost += g.Cost(v, tour[(i+1)%len(tour)])
+	}
+	return
+}
+
+// Try all permutations of 1..n-1.
+func bruteTSP(g *Mutable) (best int64, ok bool) {
+	n := g.Order()
+	tour := make([]int, n)
+	for i := range tour {
+		tour[i] = i
+	}
+	var permute func(k int)
+	permute = func(k int) {
+		if k == n {
+			for i, v := range tour {
+				if !g.Edge(v, tour[(i+1)%n]) || v == tour[(i+1)%n] {
+					return
+				}
+			}
+			if c := tourCost(g, tour); !ok || c < best {
+				best, ok = c, true
+			}
+			return
+		}
+		for i := k; i < n; i++ {
+			tour[k], tour[i] = tour[i], tour[k]
+			permute(k + 1)
+			tour[k], tour[i] = tour[i], tour[k]
+		}
+	}
+	permute(1)
+	return
+}
+
+func BenchmarkTSP(b *testing.B) {
+	n := 14
+	b.StopTimer()
+	g := New(n)
+	for v := 0; v < n; v++ {
+		for w := 0; w < n; w++ {
+			g.AddCost(v, w, int64(rand.Intn(1000)))
+		}
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = TSP(g)
+	}
+}
+
+func BenchmarkTSPApprox(b *testing.B) {
+	n := 200
+	b.StopTimer()
+	g := New(n)
+	for v := 0; v < n; v++ {
+		for w := 0; w < n; w++ {
+			g.AddCost(v, w, int64(rand.Intn(1000)))
+		}
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = TSPApprox(g)
+	}
+}